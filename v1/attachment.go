@@ -16,12 +16,16 @@ package asana
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"path"
 	"strings"
 
 	"github.com/orijtech/otils"
@@ -41,25 +45,63 @@ type Attachment struct {
 
 	Name otils.NullableString `json:"name"`
 
-	// Parent contains the information of the
-	// task that this attachment is attached to.
+	// Parent contains the information of the task, project
+	// or project brief that this attachment is attached to.
 	Parent *NamedAndIDdEntity `json:"parent,omitempty"`
 
+	// ResourceSubtype is "external" for attachments
+	// that merely link to a URL, see AttachExternalURL.
+	ResourceSubtype AttachmentSubtype `json:"resource_subtype,omitempty"`
+
+	// PermanentURL, unlike DownloadURL, does not expire but
+	// requires the viewer to be logged into Asana.
+	PermanentURL otils.NullableString `json:"permanent_url,omitempty"`
+
+	// Size is in bytes and only set for files hosted by Asana.
+	Size int64 `json:"size,omitempty"`
+
 	ViewURL otils.NullableString `json:"view_url,omitempty"`
 }
 
+// MIMEType guesses the type of the attachment's content from the extension
+// of its name since Asana doesn't report it. It is "" if there's no guess.
+func (a *Attachment) MIMEType() string {
+	mimeType := mime.TypeByExtension(path.Ext(string(a.Name)))
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return mimeType
+}
+
+type AttachmentSubtype string
+
+const (
+	AttachmentAsana    AttachmentSubtype = "asana"
+	AttachmentDropbox  AttachmentSubtype = "dropbox"
+	AttachmentGDrive   AttachmentSubtype = "gdrive"
+	AttachmentBox      AttachmentSubtype = "box"
+	AttachmentOneDrive AttachmentSubtype = "onedrive"
+	AttachmentVimeo    AttachmentSubtype = "vimeo"
+	AttachmentExternal AttachmentSubtype = "external"
+)
+
 var (
 	errEmptyAttachmentID = errors.New("expecting a non-empty attachmentID")
 	errNoAttachment      = errors.New("no attachment was received")
 )
 
 func (c *Client) FindAttachmentByID(attachmentID string) (*Attachment, error) {
+	return c.findAttachmentByID(context.Background(), attachmentID)
+}
+
+func (c *Client) findAttachmentByID(ctx context.Context, attachmentID string) (*Attachment, error) {
 	attachmentID = strings.TrimSpace(attachmentID)
 	if attachmentID == "" {
 		return nil, errEmptyAttachmentID
 	}
 	fullURL := fmt.Sprintf("%s/attachments/%s", baseURL, attachmentID)
 	req, _ := http.NewRequest("GET", fullURL, nil)
+	req = req.WithContext(ctx)
 	slurp, _, err := c.doAuthReqThenSlurpBody(req)
 	if err != nil {
 		return nil, err
@@ -87,6 +129,32 @@ type AttachmentUpload struct {
 	Body   io.Reader `json:"-"`
 	TaskID string    `json:"task_id"`
 	Name   string    `json:"name"`
+
+	// ParentID can be set instead of TaskID to attach
+	// to a task, a project or a project brief.
+	ParentID string `json:"parent,omitempty"`
+
+	// ContentType overrides the type sniffed from the first bytes of Body.
+	ContentType string `json:"-"`
+
+	// Size is the number of bytes that Body will yield. If unset, it is
+	// determined for files and other seekable or sized readers.
+	Size int64 `json:"-"`
+
+	// Progress, if set, is invoked as Body is sent with the number of bytes
+	// sent so far and the total, which is -1 if the size is unknown.
+	Progress func(sent, total int64) `json:"-"`
+}
+
+// MaxAttachmentSize is the largest file that Asana accepts as an attachment.
+const MaxAttachmentSize = 100 << 20
+
+type attachmentTooLargeError struct {
+	size int64
+}
+
+func (atle *attachmentTooLargeError) Error() string {
+	return fmt.Sprintf("attachment of %d bytes exceeds the limit of %d bytes", atle.size, MaxAttachmentSize)
 }
 
 func (au *AttachmentUpload) nonBlankFilename() string {
@@ -98,88 +166,364 @@ func (au *AttachmentUpload) nonBlankFilename() string {
 
 var errNilBody = errors.New("expecting a non-nil body")
 
+// Validate trims TaskID and ParentID, which
+// the upload then uses to pick its endpoint.
 func (au *AttachmentUpload) Validate() error {
 	if au == nil || au.Body == nil {
 		return errNilBody
 	}
-	if strings.TrimSpace(au.TaskID) == "" {
+	au.TaskID = strings.TrimSpace(au.TaskID)
+	au.ParentID = strings.TrimSpace(au.ParentID)
+	if au.TaskID == "" && au.ParentID == "" {
 		return errEmptyTaskID
 	}
 	return nil
 }
 
-// UploadAtatchment uploads an attachment to a specific task.
-// Its fields: TaskID and Body must be set otherwise it will return an error.
+// uploadURL returns the legacy per-task endpoint if TaskID
+// is set otherwise the endpoint that takes in any parent.
+func (au *AttachmentUpload) uploadURL() string {
+	if au.TaskID != "" {
+		return fmt.Sprintf("%s/tasks/%s/attachments", baseURL, au.TaskID)
+	}
+	return fmt.Sprintf("%s/attachments", baseURL)
+}
+
+// UploadAtatchment uploads an attachment to a specific task, or to the
+// project or project brief identified by ParentID. Its fields: Body and
+// one of TaskID or ParentID must be set otherwise it will return an error.
 func (c *Client) UploadAttachment(au *AttachmentUpload) (*Attachment, error) {
 	if err := au.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Step 1. Try to determine the contentType.
+	// Step 1. Reject oversized bodies before sending anything.
+	size := au.Size
+	if size <= 0 {
+		size = readerSize(au.Body)
+	}
+	if size > MaxAttachmentSize {
+		return nil, &attachmentTooLargeError{size: size}
+	}
+
+	// Step 2. Try to determine the contentType.
 	contentType, body, err := fDetectContentType(au.Body)
 	if err != nil {
 		return nil, err
 	}
+	if au.ContentType != "" {
+		contentType = au.ContentType
+	}
 
-	// Step 2:
+	// Step 3:
 	// Initiate and then make the upload.
 	prc, pwc := io.Pipe()
 	mpartW := multipart.NewWriter(pwc)
+	writeErrChan := make(chan error, 1)
 	go func() {
-		defer func() {
-			_ = mpartW.Close()
-			_ = pwc.Close()
-		}()
-
-		formFile, err := mpartW.CreateFormFile("file", au.nonBlankFilename())
-		if err != nil {
-			return
+		err := writeAttachmentForm(mpartW, au, body, contentType, size)
+		if err == nil {
+			err = mpartW.Close()
 		}
-		_, _ = io.Copy(formFile, body)
-
-		writeStringField(mpartW, "Content-Type", contentType)
-		writeStringField(mpartW, "name", au.Name)
+		// The request sees err as a failed read of its body.
+		_ = pwc.CloseWithError(err)
+		writeErrChan <- err
 	}()
 
-	fullURL := fmt.Sprintf("%s/tasks/%s/attachments", baseURL, au.TaskID)
-	req, err := http.NewRequest("POST", fullURL, prc)
+	req, err := http.NewRequest("POST", au.uploadURL(), prc)
 	if err != nil {
+		_ = prc.Close()
+		<-writeErrChan
 		return nil, err
 	}
 	req.Header.Set("Content-Type", mpartW.FormDataContentType())
 	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+
+	// Unblock the writer in case the server replied before reading all of it.
+	_ = prc.Close()
+	if werr := <-writeErrChan; werr != nil && werr != io.ErrClosedPipe {
+		return nil, werr
+	}
 	if err != nil {
 		return nil, err
 	}
 	return parseOutAttachmentFromData(slurp)
 }
 
-type AttachmentsPage struct {
-	Attachments []*Attachment `json:"data"`
+func writeAttachmentForm(mpartW *multipart.Writer, au *AttachmentUpload, body io.Reader, contentType string, size int64) error {
+	formFile, err := mpartW.CreateFormFile("file", au.nonBlankFilename())
+	if err != nil {
+		return err
+	}
+
+	// Read one byte past the limit to detect bodies that lied about their size.
+	pr := &progressReader{r: io.LimitReader(body, MaxAttachmentSize+1), total: size, progress: au.Progress}
+	n, err := io.Copy(formFile, pr)
+	if err != nil {
+		return err
+	}
+	if n > MaxAttachmentSize {
+		return &attachmentTooLargeError{size: n}
+	}
+
+	if err := mpartW.WriteField("Content-Type", contentType); err != nil {
+		return err
+	}
+	if err := mpartW.WriteField("name", au.Name); err != nil {
+		return err
+	}
+	// Only the endpoint without a task in its path needs the parent.
+	if au.TaskID == "" {
+		return mpartW.WriteField("parent", au.ParentID)
+	}
+	return nil
 }
 
-// ListAllAttachmentsForTask retrieves all the attachments for the taskID provided.
-func (c *Client) ListAllAttachmentsForTask(taskID string) (*AttachmentsPage, error) {
-	taskID = strings.TrimSpace(taskID)
-	if taskID == "" {
-		return nil, errEmptyTaskID
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	if n > 0 && pr.progress != nil {
+		pr.sent += int64(n)
+		pr.progress(pr.sent, pr.total)
 	}
-	fullURL := fmt.Sprintf("%s/tasks/%s/attachments", baseURL, taskID)
-	req, err := http.NewRequest("GET", fullURL, nil)
-	if err != nil {
+	return n, err
+}
+
+// readerSize returns the number of bytes left to read from r, or -1 if
+// that can't be determined without consuming it.
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		// *bytes.Buffer, *bytes.Reader and *strings.Reader.
+		return int64(r.Len())
+	case io.Seeker:
+		cur, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := r.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - cur
+	default:
+		return -1
+	}
+}
+
+// ExternalAttachment links a URL, e.g. of a build artifact or a
+// dashboard, to a task, project or project brief without uploading.
+type ExternalAttachment struct {
+	ParentID string `json:"parent"`
+	URL      string `json:"url"`
+	Name     string `json:"name"`
+}
+
+var (
+	errNilExternalAttachment = errors.New("expecting a non-nil externalAttachment")
+	errEmptyParentID         = errors.New("expecting a non-empty parentID")
+	errEmptyURL              = errors.New("expecting a non-empty URL")
+)
+
+func (ea *ExternalAttachment) Validate() error {
+	if ea == nil {
+		return errNilExternalAttachment
+	}
+	ea.ParentID = strings.TrimSpace(ea.ParentID)
+	if ea.ParentID == "" {
+		return errEmptyParentID
+	}
+	ea.URL = strings.TrimSpace(ea.URL)
+	if ea.URL == "" {
+		return errEmptyURL
+	}
+	return nil
+}
+
+// AttachExternalURL creates an attachment of subtype "external"
+// that links to ea.URL. If Name is unset, the URL is used.
+func (c *Client) AttachExternalURL(ea *ExternalAttachment) (*Attachment, error) {
+	if err := ea.Validate(); err != nil {
 		return nil, err
 	}
 
+	name := ea.Name
+	if name == "" {
+		name = ea.URL
+	}
+
+	// The endpoint only accepts multipart forms, even without a file.
+	buf := new(bytes.Buffer)
+	mpartW := multipart.NewWriter(buf)
+	writeStringField(mpartW, "parent", ea.ParentID)
+	writeStringField(mpartW, "resource_subtype", string(AttachmentExternal))
+	writeStringField(mpartW, "url", ea.URL)
+	writeStringField(mpartW, "name", name)
+	if err := mpartW.Close(); err != nil {
+		return nil, err
+	}
+
+	fullURL := fmt.Sprintf("%s/attachments", baseURL)
+	req, err := http.NewRequest("POST", fullURL, buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mpartW.FormDataContentType())
 	slurp, _, err := c.doAuthReqThenSlurpBody(req)
 	if err != nil {
 		return nil, err
 	}
+	return parseOutAttachmentFromData(slurp)
+}
 
-	apage := new(AttachmentsPage)
-	if err := json.Unmarshal(slurp, apage); err != nil {
-		return nil, err
+func (c *Client) DeleteAttachment(attachmentID string) error {
+	attachmentID = strings.TrimSpace(attachmentID)
+	if attachmentID == "" {
+		return errEmptyAttachmentID
+	}
+	fullURL := fmt.Sprintf("%s/attachments/%s", baseURL, attachmentID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
 	}
-	return apage, nil
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type AttachmentsPage struct {
+	Attachments []*Attachment `json:"data"`
+	Err         error         `json:"-"`
+}
+
+type attachmentsPager struct {
+	AttachmentsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// attachmentFullFields are the opt_fields that make
+// the listing return full records instead of compact ones.
+var attachmentFullFields = []string{
+	"name", "created_at", "download_url", "host", "parent", "parent.name",
+	"permanent_url", "resource_subtype", "size", "view_url",
+}
+
+type AttachmentsListOptions struct {
+	// FullRecords requests all the fields of each attachment
+	// rather than just their IDs and names.
+	FullRecords bool
+
+	// Hosts, if set, keeps only attachments hosted by any of them e.g. "asana".
+	Hosts []string
+
+	// MIMETypes, if set, keeps only attachments whose MIMEType matches any
+	// of them. A type can end in "/*" to match a whole family e.g. "image/*".
+	MIMETypes []string
+
+	Limit int
+}
+
+func (alo *AttachmentsListOptions) keep(a *Attachment) bool {
+	if alo == nil {
+		return true
+	}
+	if len(alo.Hosts) > 0 {
+		var found bool
+		for _, host := range alo.Hosts {
+			if strings.EqualFold(host, string(a.Host)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(alo.MIMETypes) == 0 {
+		return true
+	}
+	// MIME types are case-insensitive.
+	mimeType := strings.ToLower(a.MIMEType())
+	if mimeType == "" {
+		return false
+	}
+	for _, want := range alo.MIMETypes {
+		want = strings.ToLower(strings.TrimSpace(want))
+		if strings.HasSuffix(want, "/*") {
+			if strings.HasPrefix(mimeType, strings.TrimSuffix(want, "*")) {
+				return true
+			}
+		} else if want == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+func (alo *AttachmentsListOptions) urlValues() url.Values {
+	qs := make(url.Values)
+	if alo == nil {
+		return qs
+	}
+	// Filtering by host needs the host even with compact records.
+	if alo.FullRecords {
+		qs.Set("opt_fields", strings.Join(attachmentFullFields, ","))
+	} else if len(alo.Hosts) > 0 {
+		qs.Set("opt_fields", "name,host")
+	}
+	if alo.Limit > 0 {
+		qs.Set("limit", fmt.Sprintf("%d", alo.Limit))
+	}
+	return qs
+}
+
+// ListAllAttachmentsForTask retrieves all the attachments for the taskID
+// provided, page by page. opts is optional and can be nil. Pages left
+// empty by the client-side filters of opts are not delivered.
+func (c *Client) ListAllAttachmentsForTask(taskID string, opts *AttachmentsListOptions) (pagesChan chan *AttachmentsPage, cancelChan chan<- bool, err error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, nil, errEmptyTaskID
+	}
+
+	path := fmt.Sprintf("/tasks/%s/attachments", taskID)
+	if qs := opts.urlValues(); len(qs) > 0 {
+		path = fmt.Sprintf("%s?%s", path, qs.Encode())
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *AttachmentsPage)
+
+	go c.paginate(path, pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(attachmentsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+
+		var kept []*Attachment
+		for _, attachment := range pager.Attachments {
+			if opts.keep(attachment) {
+				kept = append(kept, attachment)
+			}
+		}
+		pager.Attachments = kept
+		if len(pager.Attachments) == 0 && pager.Err == nil {
+			return nil, pager.NextPage
+		}
+		return &pager.AttachmentsPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
 }
 
 func writeStringField(w *multipart.Writer, key, value string) {
@@ -201,6 +545,7 @@ func fDetectContentType(r io.Reader) (string, io.Reader, error) {
 		return "", nil, err
 	}
 
+	sniffBuf = sniffBuf[:n]
 	contentType := http.DetectContentType(sniffBuf)
 	needsRepad := !seekable
 	if seekable {
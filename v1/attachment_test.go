@@ -16,13 +16,18 @@ package asana_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/orijtech/asana/v1"
@@ -59,7 +64,7 @@ func TestFindAttachmentByID(t *testing.T) {
 		attachment, err := client.FindAttachmentByID(tt.attachmentID)
 		if tt.wantErr {
 			if err == nil {
-				t.Errorf("#%d: wanted non-nil error")
+				t.Errorf("#%d: wanted non-nil error", i)
 			}
 			continue
 		}
@@ -84,14 +89,17 @@ func TestListAllAttachmentForTask(t *testing.T) {
 	}
 	client.SetHTTPRoundTripper(&backend{route: listAllAttachmentsRoute})
 
+	allAttachments := attachmentsPageFromFile(taskID1).Attachments
+
 	tests := [...]struct {
 		taskID  string
+		opts    *asana.AttachmentsListOptions
 		wantErr bool
-		want    *asana.AttachmentsPage
+		want    []*asana.Attachment
 	}{
 		0: {
 			taskID: taskID1,
-			want:   attachmentsPageFromFile(taskID1),
+			want:   allAttachments,
 		},
 		1: {
 			taskID:  "",
@@ -101,13 +109,34 @@ func TestListAllAttachmentForTask(t *testing.T) {
 			taskID:  "  ",
 			wantErr: true,
 		},
+		3: {
+			taskID: taskID1,
+			opts:   &asana.AttachmentsListOptions{MIMETypes: []string{"image/*"}},
+			want:   allAttachments[:1],
+		},
+		4: {
+			taskID: taskID1,
+			opts:   &asana.AttachmentsListOptions{MIMETypes: []string{"application/pdf"}},
+			want:   allAttachments[1:],
+		},
+		5: {
+			// MIME types match regardless of case, wildcards included.
+			taskID: taskID1,
+			opts:   &asana.AttachmentsListOptions{MIMETypes: []string{"IMAGE/*"}},
+			want:   allAttachments[:1],
+		},
+		6: {
+			taskID: taskID1,
+			opts:   &asana.AttachmentsListOptions{MIMETypes: []string{"Application/PDF"}},
+			want:   allAttachments[1:],
+		},
 	}
 
 	for i, tt := range tests {
-		attachmentsPage, err := client.ListAllAttachmentsForTask(tt.taskID)
+		pagesChan, _, err := client.ListAllAttachmentsForTask(tt.taskID, tt.opts)
 		if tt.wantErr {
 			if err == nil {
-				t.Errorf("#%d: wanted non-nil error")
+				t.Errorf("#%d: wanted non-nil error", i)
 			}
 			continue
 		}
@@ -117,7 +146,16 @@ func TestListAllAttachmentForTask(t *testing.T) {
 			continue
 		}
 
-		gotBlob := jsonMarshal(attachmentsPage)
+		var got []*asana.Attachment
+		for page := range pagesChan {
+			if page.Err != nil {
+				t.Errorf("#%d: page err: %v", i, page.Err)
+				continue
+			}
+			got = append(got, page.Attachments...)
+		}
+
+		gotBlob := jsonMarshal(got)
 		wantBlob := jsonMarshal(tt.want)
 		if !bytes.Equal(gotBlob, wantBlob) {
 			t.Errorf("#%d:\ngotBytes:  %s\nwantBytes: %s", i, gotBlob, wantBlob)
@@ -125,6 +163,68 @@ func TestListAllAttachmentForTask(t *testing.T) {
 	}
 }
 
+func TestDownloadAttachmentToPath(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: downloadAttachmentRoute})
+
+	dir, err := ioutil.TempDir("", "asana-download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	want, err := ioutil.ReadFile("./testdata/messengerQR.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(want)
+	wantSHA256 := hex.EncodeToString(sum[:])
+
+	tests := [...]struct {
+		attachmentID string
+		wantSHA256   string
+		wantErr      bool
+	}{
+		0: {attachmentID: attachmentID1, wantSHA256: wantSHA256},
+		1: {attachmentID: attachmentID1},
+		2: {attachmentID: attachmentID1, wantSHA256: "deadbeef", wantErr: true},
+		3: {attachmentID: "  ", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		path := filepath.Join(dir, fmt.Sprintf("%d", i), "messengerQR.png")
+		download, err := client.DownloadAttachmentToPath(context.Background(), tt.attachmentID, path, tt.wantSHA256)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			if _, serr := os.Stat(path); serr == nil {
+				t.Errorf("#%d: file was saved despite the failure", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if download.SHA256 != wantSHA256 {
+			t.Errorf("#%d: got sha256 %s want %s", i, download.SHA256, wantSHA256)
+		}
+		got, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Errorf("#%d: reading the download: %v", i, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("#%d: downloaded content differs", i)
+		}
+	}
+}
+
 func fFromFile(path string) io.Reader {
 	f, _ := os.Open(path)
 	return f
@@ -165,6 +265,24 @@ func TestUploadAttachment(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		4: {
+			req: &asana.AttachmentUpload{
+				TaskID: taskID1,
+				Name:   "Too large",
+				Body:   strings.NewReader("tiny"),
+				Size:   asana.MaxAttachmentSize + 1,
+			},
+			wantErr: true,
+		},
+		5: {
+			// Errors reading the body must not be swallowed.
+			req: &asana.AttachmentUpload{
+				TaskID: taskID1,
+				Name:   "Broken",
+				Body:   io.MultiReader(strings.NewReader("partial content "), &erroringReader{}),
+			},
+			wantErr: true,
+		},
 	}
 
 	for i, tt := range tests {
@@ -189,15 +307,165 @@ func TestUploadAttachment(t *testing.T) {
 	}
 }
 
+func TestUploadAttachmentToParent(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: uploadAttachmentToParentRoute})
+
+	attachment, err := client.UploadAttachment(&asana.AttachmentUpload{
+		// A blank TaskID must not route the upload to a task.
+		TaskID:   "  ",
+		ParentID: " " + projectID1 + " ",
+		Name:     "Messenger QR code",
+		Body:     fFromFile("./testdata/messengerQR.png"),
+	})
+	if err != nil {
+		t.Fatalf("uploading: %v", err)
+	}
+	gotBlob := jsonMarshal(attachment)
+	wantBlob := jsonMarshal(attachmentFromFile(attachmentID1))
+	if !bytes.Equal(gotBlob, wantBlob) {
+		t.Errorf("\ngotBytes:  %s\nwantBytes: %s", gotBlob, wantBlob)
+	}
+}
+
+func TestAttachExternalURL(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: attachExternalURLRoute})
+
+	tests := [...]struct {
+		req     *asana.ExternalAttachment
+		wantErr bool
+	}{
+		0: {
+			req: &asana.ExternalAttachment{
+				ParentID: projectID1,
+				URL:      externalURL1,
+				Name:     "Build logs",
+			},
+		},
+		1: {
+			// The name defaults to the URL.
+			req: &asana.ExternalAttachment{
+				ParentID: " " + projectID1,
+				URL:      externalURL1 + " ",
+			},
+		},
+		2: {req: nil, wantErr: true},
+		3: {req: &asana.ExternalAttachment{URL: externalURL1}, wantErr: true},
+		4: {req: &asana.ExternalAttachment{ParentID: projectID1}, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		attachment, err := client.AttachExternalURL(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if attachment == nil {
+			t.Errorf("#%d: expected a non-nil attachment", i)
+		}
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: deleteAttachmentRoute})
+
+	tests := [...]struct {
+		attachmentID string
+		wantErr      bool
+	}{
+		0: {attachmentID: attachmentID1},
+		1: {attachmentID: " " + attachmentID1 + " "},
+		2: {attachmentID: "", wantErr: true},
+		3: {attachmentID: "unknown", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		err := client.DeleteAttachment(tt.attachmentID)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+		}
+	}
+}
+
+type erroringReader struct{}
+
+func (er *erroringReader) Read([]byte) (int, error) {
+	return 0, fmt.Errorf("disk on fire")
+}
+
+func TestUploadAttachmentProgress(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: uploadAttachmentRoute})
+
+	blob, err := ioutil.ReadFile("./testdata/messengerQR.png")
+	if err != nil {
+		t.Fatalf("reading the testdata: %v", err)
+	}
+
+	var lastSent, lastTotal int64
+	_, err = client.UploadAttachment(&asana.AttachmentUpload{
+		TaskID:      taskID1,
+		Name:        "Messenger QR code",
+		Body:        bytes.NewReader(blob),
+		ContentType: "image/x-custom",
+		Progress: func(sent, total int64) {
+			if sent < lastSent {
+				t.Errorf("progress went backwards: %d after %d", sent, lastSent)
+			}
+			lastSent, lastTotal = sent, total
+		},
+	})
+	if err != nil {
+		t.Fatalf("uploading: %v", err)
+	}
+	if want := int64(len(blob)); lastSent != want || lastTotal != want {
+		t.Errorf("last progress: got %d/%d want %d/%d", lastSent, lastTotal, want, want)
+	}
+}
+
 const (
 	paToken1 = "pa-token-1"
 
 	attachmentID1 = "5678"
 	taskID1       = "task-id-1"
+	projectID1    = "project-id-1"
+	externalURL1  = "https://ci.example.com/builds/1"
 
 	findAttachmentByIDRoute = "find-attachment-by-id"
 	uploadAttachmentRoute   = "upload-attachment"
 	listAllAttachmentsRoute = "list-all-attachments-route"
+	downloadAttachmentRoute = "download-attachment"
+
+	uploadAttachmentToParentRoute = "upload-attachment-to-parent"
+	attachExternalURLRoute        = "attach-external-url"
+	deleteAttachmentRoute         = "delete-attachment"
 )
 
 var authorizedTokens = map[string]bool{
@@ -269,6 +537,14 @@ func (b *backend) RoundTrip(req *http.Request) (*http.Response, error) {
 		return b.uploadAttachmentRoundTrip(req)
 	case listAllAttachmentsRoute:
 		return b.listAllAttachmentsRoundTrip(req)
+	case downloadAttachmentRoute:
+		return b.downloadAttachmentRoundTrip(req)
+	case uploadAttachmentToParentRoute:
+		return b.uploadAttachmentToParentRoundTrip(req)
+	case attachExternalURLRoute:
+		return b.attachExternalURLRoundTrip(req)
+	case deleteAttachmentRoute:
+		return b.deleteAttachmentRoundTrip(req)
 	default:
 		return unknownRouteResp, nil
 	}
@@ -285,6 +561,35 @@ func makeResp(status string, code int, body io.ReadCloser) *http.Response {
 	return resp
 }
 
+type scriptedResp struct {
+	code int
+	body string
+}
+
+// scriptedBackend replies to successive requests with
+// successive responses, recording the requests it received.
+type scriptedBackend struct {
+	sync.Mutex
+	resps []scriptedResp
+	reqs  []*http.Request
+}
+
+var _ http.RoundTripper = (*scriptedBackend)(nil)
+
+func (sb *scriptedBackend) RoundTrip(req *http.Request) (*http.Response, error) {
+	sb.Lock()
+	defer sb.Unlock()
+
+	sb.reqs = append(sb.reqs, req)
+	if len(sb.resps) == 0 {
+		return makeResp("script exhausted", http.StatusInternalServerError, nil), nil
+	}
+	resp := sb.resps[0]
+	sb.resps = sb.resps[1:]
+	body := ioutil.NopCloser(strings.NewReader(resp.body))
+	return makeResp(http.StatusText(resp.code), resp.code, body), nil
+}
+
 var (
 	unknownRouteResp            = makeResp("unknown route", http.StatusNotFound, nil)
 	invalidAuthResp             = makeResp("invalid authentication, make sure to pass \"Bearer <PA_TOKEN>\" in your headers", http.StatusBadRequest, nil)
@@ -400,6 +705,84 @@ func (b *backend) findAttachmentByIDRoundTrip(req *http.Request) (*http.Response
 	return makeRespFromFile(diskPath)
 }
 
+func (b *backend) downloadAttachmentRoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "app.asana.com" {
+		return b.findAttachmentByIDRoundTrip(req)
+	}
+
+	// Otherwise this is the pre-signed download URL
+	// which must not receive our credentials.
+	if req.Header.Get("Authorization") != "" {
+		return makeResp("unexpected Authorization header", http.StatusBadRequest, nil), nil
+	}
+	return makeRespFromFile("./testdata/messengerQR.png")
+}
+
+func (b *backend) uploadAttachmentToParentRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if got, want := req.URL.Path, "/api/1.0/attachments"; got != want {
+		return makeResp(fmt.Sprintf("got path %q want %q", got, want), http.StatusBadRequest, nil), nil
+	}
+	if err := req.ParseMultipartForm(10e9); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	if got, want := req.FormValue("parent"), projectID1; got != want {
+		return makeResp(fmt.Sprintf("got parent %q want %q", got, want), http.StatusBadRequest, nil), nil
+	}
+	if req.FormValue("name") == "" {
+		return makeResp("\"name\" should have been set", http.StatusBadRequest, nil), nil
+	}
+	file, _, err := req.FormFile("file")
+	if err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	file.Close()
+	return makeRespFromFile(attachmentResponsePath(attachmentID1))
+}
+
+func (b *backend) attachExternalURLRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if got, want := req.URL.Path, "/api/1.0/attachments"; got != want {
+		return makeResp(fmt.Sprintf("got path %q want %q", got, want), http.StatusBadRequest, nil), nil
+	}
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	wantName := req.FormValue("name")
+	if wantName != "Build logs" {
+		wantName = externalURL1
+	}
+	wantFields := map[string]string{
+		"parent":           projectID1,
+		"resource_subtype": "external",
+		"url":              externalURL1,
+		"name":             wantName,
+	}
+	for key, want := range wantFields {
+		if got := req.FormValue(key); got != want {
+			return makeResp(fmt.Sprintf("%q: got %q want %q", key, got, want), http.StatusBadRequest, nil), nil
+		}
+	}
+	if _, _, err := req.FormFile("file"); err == nil {
+		return makeResp("unexpected file in the form", http.StatusBadRequest, nil), nil
+	}
+	return makeRespFromFile(attachmentResponsePath(attachmentID1))
+}
+
+func (b *backend) deleteAttachmentRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "DELETE"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if got, want := req.URL.Path, "/api/1.0/attachments/"+attachmentID1; got != want {
+		return makeResp(fmt.Sprintf("got path %q want %q", got, want), http.StatusNotFound, nil), nil
+	}
+	return makeResp("200 OK", http.StatusOK, ioutil.NopCloser(strings.NewReader(`{"data":{}}`))), nil
+}
+
 func makeRespFromFile(path string) (*http.Response, error) {
 	f, err := os.Open(path)
 	if err != nil {
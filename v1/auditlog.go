@@ -0,0 +1,310 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AuditLogActorType string
+
+const (
+	ActorUser                  AuditLogActorType = "user"
+	ActorAsana                 AuditLogActorType = "asana"
+	ActorAsanaSupport          AuditLogActorType = "asana_support"
+	ActorAnonymous             AuditLogActorType = "anonymous"
+	ActorExternalAdministrator AuditLogActorType = "external_administrator"
+)
+
+// AuditLogActor is who or what caused an audit log event.
+type AuditLogActor struct {
+	Type  AuditLogActorType `json:"actor_type"`
+	GID   string            `json:"gid,omitempty"`
+	Name  string            `json:"name,omitempty"`
+	Email string            `json:"email,omitempty"`
+}
+
+// AuditLogResource is the object that an audit log event is about.
+type AuditLogResource struct {
+	Type    string `json:"resource_type"`
+	Subtype string `json:"resource_subtype,omitempty"`
+	GID     string `json:"gid,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+type AuditLogContextType string
+
+const (
+	ContextWeb          AuditLogContextType = "web"
+	ContextDesktop      AuditLogContextType = "desktop"
+	ContextMobile       AuditLogContextType = "mobile"
+	ContextAsanaSupport AuditLogContextType = "asana_support"
+	ContextAsana        AuditLogContextType = "asana"
+	ContextEmail        AuditLogContextType = "email"
+	ContextAPI          AuditLogContextType = "api"
+)
+
+// AuditLogContext describes where an audit log event originated.
+type AuditLogContext struct {
+	Type AuditLogContextType `json:"context_type"`
+
+	// APIAuthenticationMethod is set for ContextAPI
+	// e.g. "personal_access_token" or "oauth".
+	APIAuthenticationMethod string `json:"api_authentication_method,omitempty"`
+	OAuthAppName            string `json:"oauth_app_name,omitempty"`
+
+	ClientIPAddress string `json:"client_ip_address,omitempty"`
+	UserAgent       string `json:"user_agent,omitempty"`
+
+	// RuleName is set for events caused by a rule.
+	RuleName string `json:"rule_name,omitempty"`
+}
+
+type AuditLogEvent struct {
+	GID       string     `json:"gid"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// EventType is e.g. "user_login_succeeded" and
+	// EventCategory the group it belongs to e.g. "logins".
+	EventType     string `json:"event_type"`
+	EventCategory string `json:"event_category,omitempty"`
+
+	Actor    *AuditLogActor    `json:"actor,omitempty"`
+	Resource *AuditLogResource `json:"resource,omitempty"`
+	Context  *AuditLogContext  `json:"context,omitempty"`
+
+	// Details are left raw because their shape depends
+	// on EventType, see DecodeDetails.
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// DecodeDetails unmarshals the event's details into v.
+func (ale *AuditLogEvent) DecodeDetails(v interface{}) error {
+	if len(ale.Details) == 0 {
+		return nil
+	}
+	return json.Unmarshal(ale.Details, v)
+}
+
+// AuditLogChangeDetails are the details of
+// events about a field changing its value.
+type AuditLogChangeDetails struct {
+	OldValue json.RawMessage `json:"old_value,omitempty"`
+	NewValue json.RawMessage `json:"new_value,omitempty"`
+	Group    string          `json:"group,omitempty"`
+	Method   []string        `json:"method,omitempty"`
+}
+
+type AuditLogQuery struct {
+	WorkspaceID string `json:"-"`
+
+	// StartAt and EndAt bound the creation times of the events.
+	StartAt *time.Time `json:"start_at,omitempty"`
+	EndAt   *time.Time `json:"end_at,omitempty"`
+
+	// EventTypes if set only returns events of those types.
+	EventTypes []string `json:"-"`
+
+	ActorType  AuditLogActorType `json:"actor_type,omitempty"`
+	ActorID    string            `json:"actor_gid,omitempty"`
+	ResourceID string            `json:"resource_gid,omitempty"`
+
+	// Offset resumes from where a previous
+	// listing left off, see AuditLogEventsPage.
+	Offset string `json:"offset,omitempty"`
+
+	Limit int `json:"limit,omitempty"`
+}
+
+var (
+	errNilAuditLogQuery = errors.New("expecting a non-nil auditLogQuery")
+	errEndBeforeStart   = errors.New("endAt cannot be before startAt")
+)
+
+func (alq *AuditLogQuery) Validate() error {
+	if alq == nil {
+		return errNilAuditLogQuery
+	}
+	if strings.TrimSpace(alq.WorkspaceID) == "" {
+		return errEmptyWorkspace
+	}
+	if alq.StartAt != nil && alq.EndAt != nil && alq.EndAt.Before(*alq.StartAt) {
+		return errEndBeforeStart
+	}
+	return nil
+}
+
+func (alq *AuditLogQuery) urlValues() url.Values {
+	qs := make(url.Values)
+	if alq.StartAt != nil {
+		qs.Set("start_at", alq.StartAt.UTC().Format(time.RFC3339))
+	}
+	if alq.EndAt != nil {
+		qs.Set("end_at", alq.EndAt.UTC().Format(time.RFC3339))
+	}
+	// The API only filters by a single event type, any
+	// more than that are instead filtered out locally.
+	if len(alq.EventTypes) == 1 {
+		qs.Set("event_type", alq.EventTypes[0])
+	}
+	if alq.ActorType != "" {
+		qs.Set("actor_type", string(alq.ActorType))
+	}
+	if id := strings.TrimSpace(alq.ActorID); id != "" {
+		qs.Set("actor_gid", id)
+	}
+	if id := strings.TrimSpace(alq.ResourceID); id != "" {
+		qs.Set("resource_gid", id)
+	}
+	if offset := strings.TrimSpace(alq.Offset); offset != "" {
+		qs.Set("offset", offset)
+	}
+	if alq.Limit > 0 {
+		qs.Set("limit", strconv.Itoa(alq.Limit))
+	}
+	return qs
+}
+
+func (alq *AuditLogQuery) keep(event *AuditLogEvent) bool {
+	if len(alq.EventTypes) < 2 {
+		return true
+	}
+	for _, eventType := range alq.EventTypes {
+		if event.EventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type AuditLogEventsPage struct {
+	Events []*AuditLogEvent `json:"data"`
+
+	// Offset is where to resume the listing from after this
+	// page, which can be stored and later set on AuditLogQuery.
+	Offset string `json:"-"`
+
+	Err error `json:"-"`
+}
+
+type auditLogEventsPager struct {
+	AuditLogEventsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListAuditLogEvents lists the audit log events of a workspace, oldest
+// first. Asana keeps handing out offsets even once all the events have
+// been read, so the listing ends at the first page without any events.
+func (c *Client) ListAuditLogEvents(alq *AuditLogQuery) (pagesChan chan *AuditLogEventsPage, cancelChan chan<- bool, err error) {
+	if err := alq.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *AuditLogEventsPage)
+
+	offset := strings.TrimSpace(alq.Offset)
+	path := fmt.Sprintf("/workspaces/%s/audit_log_events?%s", strings.TrimSpace(alq.WorkspaceID), alq.urlValues().Encode())
+	go c.paginate(path, pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(auditLogEventsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		np := pager.NextPage
+		if np != nil && np.Offset != "" {
+			offset = np.Offset
+		}
+
+		page := pager.AuditLogEventsPage
+		fetched := len(page.Events)
+		page.Offset = offset
+		page.Events = page.Events[:0]
+		for _, event := range pager.Events {
+			if event != nil && alq.keep(event) {
+				page.Events = append(page.Events, event)
+			}
+		}
+
+		if fetched == 0 {
+			// Asana's offsets never run out, so an empty page is the last.
+			np = nil
+		}
+		return &page, np
+	})
+
+	return pagesChan, cancel, nil
+}
+
+// AuditLogExport summarizes a run of ExportAuditLogEvents.
+type AuditLogExport struct {
+	Events int
+
+	// Offset is where the next export should resume from.
+	Offset string
+}
+
+// ExportAuditLogEvents writes the events matching the query to w as
+// newline delimited JSON, one event per line, for ingestion by e.g.
+// a SIEM. The returned export's Offset should be stored and set on the
+// query of the next export so that each run only picks up new events.
+// On error, the export reflects what was written before the failure.
+func (c *Client) ExportAuditLogEvents(ctx context.Context, alq *AuditLogQuery, w io.Writer) (*AuditLogExport, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	pagesChan, cancel, err := c.ListAuditLogEvents(alq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		cancel <- true
+		go func() {
+			for range pagesChan {
+			}
+		}()
+	}()
+
+	export := &AuditLogExport{Offset: strings.TrimSpace(alq.Offset)}
+	enc := json.NewEncoder(w)
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			return export, err
+		}
+		for _, event := range page.Events {
+			if err := enc.Encode(event); err != nil {
+				return export, err
+			}
+			export.Events++
+		}
+		// Only advance the offset once the whole page has been written.
+		export.Offset = page.Offset
+
+		if err := ctx.Err(); err != nil {
+			return export, err
+		}
+	}
+	return export, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const auditLogPath = "/api/1.0/workspaces/14916/audit_log_events"
+
+func auditLogResps() []scriptedResp {
+	return []scriptedResp{
+		{code: http.StatusOK, body: `{"data": [
+		  {"gid": "1", "event_type": "user_login_succeeded", "event_category": "logins",
+		   "actor": {"actor_type": "user", "gid": "7", "email": "ada@example.com"},
+		   "context": {"context_type": "web", "client_ip_address": "10.0.0.1"}},
+		  {"gid": "2", "event_type": "task_deleted", "resource": {"resource_type": "task", "gid": "9"},
+		   "details": {"old_value": "Budget", "new_value": null}}
+		], "next_page": {"offset": "o1", "path": "/workspaces/14916/audit_log_events?offset=o1"}}`},
+		{code: http.StatusOK, body: `{"data": [
+		  {"gid": "3", "event_type": "user_login_failed", "actor": {"actor_type": "anonymous"}}
+		], "next_page": {"offset": "o2", "path": "/workspaces/14916/audit_log_events?offset=o2"}}`},
+		// All caught up, yet another offset is handed out.
+		{code: http.StatusOK, body: `{"data": [],
+		  "next_page": {"offset": "o2", "path": "/workspaces/14916/audit_log_events?offset=o2"}}`},
+	}
+}
+
+func TestExportAuditLogEvents(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		query      *asana.AuditLogQuery
+		resps      []scriptedResp
+		wantErr    bool
+		wantGIDs   []string
+		wantOffset string
+		wantQuery  string
+	}{
+		0: {query: nil, wantErr: true},
+		1: {query: &asana.AuditLogQuery{}, wantErr: true},
+		2: {
+			query:      &asana.AuditLogQuery{WorkspaceID: "14916"},
+			resps:      auditLogResps(),
+			wantGIDs:   []string{"1", "2", "3"},
+			wantOffset: "o2",
+			wantQuery:  "",
+		},
+		// Resuming from a stored offset with a single event type.
+		3: {
+			query: &asana.AuditLogQuery{
+				WorkspaceID: "14916", Offset: "o1",
+				EventTypes: []string{"user_login_failed"},
+			},
+			resps:      auditLogResps()[1:],
+			wantGIDs:   []string{"3"},
+			wantOffset: "o2",
+			wantQuery:  "event_type=user_login_failed&offset=o1",
+		},
+		// Multiple event types are filtered locally.
+		4: {
+			query: &asana.AuditLogQuery{
+				WorkspaceID: "14916",
+				EventTypes:  []string{"user_login_succeeded", "user_login_failed"},
+			},
+			resps:      auditLogResps(),
+			wantGIDs:   []string{"1", "3"},
+			wantOffset: "o2",
+			wantQuery:  "",
+		},
+		// A failure keeps the offset of the last complete page.
+		5: {
+			query: &asana.AuditLogQuery{WorkspaceID: "14916"},
+			resps: append(auditLogResps()[:1], scriptedResp{
+				code: http.StatusInternalServerError, body: `{"errors": [{"message": "oops"}]}`,
+			}),
+			wantErr:    true,
+			wantGIDs:   []string{"1", "2"},
+			wantOffset: "o1",
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: tt.resps}
+		client.SetHTTPRoundTripper(be)
+
+		buf := new(bytes.Buffer)
+		export, err := client.ExportAuditLogEvents(context.Background(), tt.query, buf)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+		} else if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+		}
+		if export == nil {
+			if len(tt.wantGIDs) > 0 {
+				t.Errorf("#%d: expected a non-nil export", i)
+			}
+			continue
+		}
+
+		var gids []string
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			event := new(asana.AuditLogEvent)
+			if err := json.Unmarshal([]byte(line), event); err != nil {
+				t.Errorf("#%d: line %q is not an event: %v", i, line, err)
+				continue
+			}
+			gids = append(gids, event.GID)
+		}
+		if g, w := strings.Join(gids, ","), strings.Join(tt.wantGIDs, ","); g != w {
+			t.Errorf("#%d: got events %q want %q", i, g, w)
+		}
+		if g, w := export.Events, len(tt.wantGIDs); g != w {
+			t.Errorf("#%d: got count %d want %d", i, g, w)
+		}
+		if g, w := export.Offset, tt.wantOffset; g != w {
+			t.Errorf("#%d: got offset %q want %q", i, g, w)
+		}
+
+		if len(be.reqs) == 0 {
+			t.Errorf("#%d: expected at least one request", i)
+			continue
+		}
+		if g, w := be.reqs[0].URL.Path, auditLogPath; g != w {
+			t.Errorf("#%d: got path %q want %q", i, g, w)
+		}
+		if !tt.wantErr {
+			if g, w := be.reqs[0].URL.RawQuery, tt.wantQuery; g != w {
+				t.Errorf("#%d: got query %q want %q", i, g, w)
+			}
+		}
+	}
+}
+
+func TestAuditLogEventDecodeDetails(t *testing.T) {
+	event := new(asana.AuditLogEvent)
+	blob := `{"gid": "2", "event_type": "task_name_changed",
+	  "details": {"old_value": "Budget", "new_value": "Budget 2018", "method": ["web"]}}`
+	if err := json.Unmarshal([]byte(blob), event); err != nil {
+		t.Fatalf("unmarshaling: %v", err)
+	}
+	details := new(asana.AuditLogChangeDetails)
+	if err := event.DecodeDetails(details); err != nil {
+		t.Fatalf("decoding details: %v", err)
+	}
+	if g, w := string(details.NewValue), `"Budget 2018"`; g != w {
+		t.Errorf("got new value %s want %s", g, w)
+	}
+	if len(details.Method) != 1 || details.Method[0] != "web" {
+		t.Errorf("unexpected method: %v", details.Method)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/orijtech/otils"
+)
+
+// maxBatchActions is the most actions that
+// the API accepts in a single batch request.
+const maxBatchActions = 10
+
+type BatchAction struct {
+	Method string `json:"method"`
+
+	// RelativePath is the path of the endpoint
+	// without the API's prefix e.g. "/tasks/1234".
+	RelativePath string `json:"relative_path"`
+
+	Data    interface{}            `json:"data,omitempty"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// Batch collects actions to be sent with Client.DoBatch.
+// Its methods can be chained, for example:
+//
+//	asana.NewBatch().Put("/tasks/1", update).Delete("/tasks/2")
+type Batch struct {
+	actions []*BatchAction
+}
+
+func NewBatch() *Batch {
+	return new(Batch)
+}
+
+// Add queues an action. Its result will be at the
+// same index in the results returned by DoBatch.
+func (b *Batch) Add(method, relativePath string, data interface{}) *Batch {
+	b.actions = append(b.actions, &BatchAction{
+		Method:       strings.ToLower(method),
+		RelativePath: relativePath,
+		Data:         data,
+	})
+	return b
+}
+
+func (b *Batch) Get(relativePath string) *Batch {
+	return b.Add("GET", relativePath, nil)
+}
+
+func (b *Batch) Post(relativePath string, data interface{}) *Batch {
+	return b.Add("POST", relativePath, data)
+}
+
+func (b *Batch) Put(relativePath string, data interface{}) *Batch {
+	return b.Add("PUT", relativePath, data)
+}
+
+func (b *Batch) Delete(relativePath string) *Batch {
+	return b.Add("DELETE", relativePath, nil)
+}
+
+func (b *Batch) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.actions)
+}
+
+type BatchResult struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+
+	// Err is set if the action failed, in which case it is
+	// an *HTTPError, or if its chunk of the batch couldn't be sent.
+	Err error `json:"-"`
+}
+
+// Decode unmarshals the "data" of the action's response into save.
+func (br *BatchResult) Decode(save interface{}) error {
+	if br.Err != nil {
+		return br.Err
+	}
+	return json.Unmarshal(br.Body, &dataWrap{Data: save})
+}
+
+func (br *BatchResult) Task() (*Task, error) {
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	return parseOutTaskFromData(br.Body)
+}
+
+func (br *BatchResult) Project() (*Project, error) {
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	return parseOutProjectFromData(br.Body)
+}
+
+var (
+	errNilBatch   = errors.New("expecting a non-nil batch")
+	errEmptyBatch = errors.New("expecting at least one action in the batch")
+)
+
+func (b *Batch) Validate() error {
+	if b == nil {
+		return errNilBatch
+	}
+	if len(b.actions) == 0 {
+		return errEmptyBatch
+	}
+	for i, action := range b.actions {
+		if action.Method == "" {
+			return fmt.Errorf("action #%d: expecting a non-empty method", i)
+		}
+		if !strings.HasPrefix(action.RelativePath, "/") {
+			return fmt.Errorf("action #%d: relative path %q must begin with \"/\"", i, action.RelativePath)
+		}
+	}
+	return nil
+}
+
+type batchRequest struct {
+	Actions []*BatchAction `json:"actions"`
+}
+
+type batchResultsWrap struct {
+	Results []*BatchResult `json:"data"`
+}
+
+// DoBatch submits the actions of the batch, in chunks of 10 if there are
+// more than that, and returns a result for every action in the same order.
+// The returned error is only for an invalid batch; failures of individual
+// actions, or of a whole chunk, are reported in each result's Err.
+func (c *Client) DoBatch(b *Batch) ([]*BatchResult, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+
+	results := make([]*BatchResult, 0, len(b.actions))
+	for start := 0; start < len(b.actions); start += maxBatchActions {
+		end := start + maxBatchActions
+		if end > len(b.actions) {
+			end = len(b.actions)
+		}
+		results = append(results, c.doBatchChunk(b.actions[start:end])...)
+	}
+	return results, nil
+}
+
+func (c *Client) doBatchChunk(actions []*BatchAction) []*BatchResult {
+	failAll := func(err error) []*BatchResult {
+		results := make([]*BatchResult, len(actions))
+		for i := range results {
+			results[i] = &BatchResult{Err: err}
+		}
+		return results
+	}
+
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", "/batch", &batchRequest{Actions: actions})
+	if err != nil {
+		return failAll(err)
+	}
+
+	brw := new(batchResultsWrap)
+	if err := json.Unmarshal(slurp, brw); err != nil {
+		return failAll(err)
+	}
+	if len(brw.Results) != len(actions) {
+		return failAll(fmt.Errorf("got %d results for %d actions", len(brw.Results), len(actions)))
+	}
+
+	for i, result := range brw.Results {
+		if result == nil {
+			// Keep the results lined up with the actions.
+			action := actions[i]
+			brw.Results[i] = &BatchResult{
+				Err: fmt.Errorf("no result for action %s %s", strings.ToUpper(action.Method), action.RelativePath),
+			}
+			continue
+		}
+		if !otils.StatusOK(result.StatusCode) {
+			msg := string(result.Body)
+			if msg == "" {
+				msg = http.StatusText(result.StatusCode)
+			}
+			result.Err = &HTTPError{msg: msg, code: result.StatusCode}
+		}
+	}
+	return brw.Results
+}
@@ -0,0 +1,170 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestDoBatchChunking(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	okResults := func(from, n int) string {
+		var results []string
+		for i := from; i < from+n; i++ {
+			results = append(results, fmt.Sprintf(`{"status_code": 200, "body": {"data": {"id": %d}}}`, i))
+		}
+		return fmt.Sprintf(`{"data": [%s]}`, strings.Join(results, ","))
+	}
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: okResults(0, 10)},
+			{code: http.StatusOK, body: okResults(10, 2)},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	batch := asana.NewBatch()
+	for i := 0; i < 12; i++ {
+		batch.Get(fmt.Sprintf("/tasks/%d", i))
+	}
+	results, err := client.DoBatch(batch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if g, w := len(be.reqs), 2; g != w {
+		t.Fatalf("got %d requests want %d", g, w)
+	}
+	for i, wantActions := range []int{10, 2} {
+		req := be.reqs[i]
+		if g, w := req.URL.Path, "/api/1.0/batch"; g != w {
+			t.Errorf("request #%d: got path %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		sent := new(struct {
+			Data struct {
+				Actions []*asana.BatchAction `json:"actions"`
+			} `json:"data"`
+		})
+		if err := json.Unmarshal(blob, sent); err != nil {
+			t.Errorf("request #%d: unmarshaling the body: %v", i, err)
+			continue
+		}
+		if g := len(sent.Data.Actions); g != wantActions {
+			t.Errorf("request #%d: got %d actions want %d", i, g, wantActions)
+		}
+	}
+
+	if g, w := len(results), 12; g != w {
+		t.Fatalf("got %d results want %d", g, w)
+	}
+	for i, result := range results {
+		task, err := result.Task()
+		if err != nil {
+			t.Errorf("result #%d: unexpected error: %v", i, err)
+			continue
+		}
+		if task.ID != int64(i) {
+			t.Errorf("result #%d: got task %d", i, task.ID)
+		}
+	}
+}
+
+func TestDoBatchPerActionErrors(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: `{"data": [
+			  {"status_code": 201, "body": {"data": {"id": 1, "name": "created"}}},
+			  {"status_code": 404, "body": {"errors": [{"message": "Unknown object"}]}},
+			  null,
+			  {"status_code": 403}` + strings.Repeat(`,
+			  {"status_code": 200, "body": {"data": {}}}`, 6) + `
+			]}`},
+			// The whole second chunk fails.
+			{code: http.StatusInternalServerError, body: `{"errors": [{"message": "oops"}]}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	batch := asana.NewBatch().
+		Post("/tasks", map[string]string{"name": "created"}).
+		Get("/tasks/404").
+		Delete("/tasks/3").
+		Put("/tasks/4", map[string]string{"name": "forbidden"})
+	for i := 0; i < 6; i++ {
+		batch.Get(fmt.Sprintf("/tasks/1%d", i))
+	}
+	batch.Get("/tasks/chunk-2")
+
+	results, err := client.DoBatch(batch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := len(results), batch.Len(); g != w {
+		t.Fatalf("got %d results want %d", g, w)
+	}
+	for i, result := range results {
+		if result == nil {
+			t.Fatalf("result #%d: unexpectedly nil", i)
+		}
+	}
+
+	if task, err := results[0].Task(); err != nil || task.Name != "created" {
+		t.Errorf("result #0: got (%+v, %v)", task, err)
+	}
+
+	tests := [...]struct {
+		index    int
+		wantCode int
+	}{
+		0: {index: 1, wantCode: http.StatusNotFound},
+		1: {index: 3, wantCode: http.StatusForbidden},
+		2: {index: 10, wantCode: http.StatusInternalServerError},
+	}
+	for i, tt := range tests {
+		herr, ok := results[tt.index].Err.(*asana.HTTPError)
+		if !ok {
+			t.Errorf("#%d: got err %#v want an *HTTPError", i, results[tt.index].Err)
+			continue
+		}
+		if g, w := herr.Code(), tt.wantCode; g != w {
+			t.Errorf("#%d: got code %d want %d", i, g, w)
+		}
+	}
+
+	// The null result still gets an error rather than a nil entry.
+	if _, err := results[2].Task(); err == nil || !strings.Contains(err.Error(), "/tasks/3") {
+		t.Errorf("null result: got err %v", err)
+	}
+	if err := results[2].Decode(new(asana.Task)); err == nil {
+		t.Errorf("null result: expected Decode to fail")
+	}
+}
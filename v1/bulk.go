@@ -0,0 +1,242 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BulkOperation is a single unit of work of a bulk run, typically
+// a closure around one of the client's methods e.g.
+//
+//	func(ctx context.Context) (interface{}, error) { return nil, client.DeleteTask(taskID) }
+//
+// It is handed the context of RunBulk so that long running
+// operations can give up once the run is cancelled.
+type BulkOperation func(ctx context.Context) (interface{}, error)
+
+type BulkRequest struct {
+	Operations []BulkOperation
+
+	// Concurrency is how many operations run at once.
+	Concurrency int
+
+	// Pacing is the minimum time between the starts of
+	// any two operations, to stay within rate limits.
+	Pacing time.Duration
+
+	// StopOnError stops starting new operations after the first
+	// failure. Those that were already running are left to finish.
+	StopOnError bool
+
+	// Resume is the result of a previous run of the same operations.
+	// Operations that succeeded in it are not run again.
+	Resume *BulkResult
+}
+
+type BulkItemResult struct {
+	Index int
+	Value interface{}
+	Err   error
+
+	// Skipped is set for operations that were not run
+	// because the run was stopped or cancelled.
+	Skipped bool
+}
+
+// Done reports whether the operation ran successfully.
+func (bir *BulkItemResult) Done() bool {
+	return bir != nil && !bir.Skipped && bir.Err == nil
+}
+
+type BulkResult struct {
+	// Items holds a result per operation, in
+	// the same order as the operations.
+	Items []*BulkItemResult
+}
+
+func (br *BulkResult) Failed() []*BulkItemResult {
+	var failed []*BulkItemResult
+	for _, item := range br.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Remaining returns the indices of the operations that
+// either failed or were skipped, which a resumed run retries.
+func (br *BulkResult) Remaining() []int {
+	var remaining []int
+	for _, item := range br.Items {
+		if !item.Done() {
+			remaining = append(remaining, item.Index)
+		}
+	}
+	return remaining
+}
+
+// BulkError summarizes the failures of a bulk run.
+type BulkError struct {
+	Failed  int
+	Skipped int
+	Total   int
+
+	// First is the error of the lowest indexed failed operation.
+	First error
+}
+
+func (be *BulkError) Error() string {
+	msg := fmt.Sprintf("%d of %d operations failed", be.Failed, be.Total)
+	if be.Skipped > 0 {
+		msg = fmt.Sprintf("%s, %d were skipped", msg, be.Skipped)
+	}
+	if be.First != nil {
+		msg = fmt.Sprintf("%s; first error: %v", msg, be.First)
+	}
+	return msg
+}
+
+// Err returns a *BulkError if any operation failed or was skipped.
+func (br *BulkResult) Err() error {
+	be := &BulkError{Total: len(br.Items)}
+	for _, item := range br.Items {
+		switch {
+		case item.Skipped:
+			be.Skipped += 1
+		case item.Err != nil:
+			be.Failed += 1
+			if be.First == nil {
+				be.First = item.Err
+			}
+		}
+	}
+	if be.Failed == 0 && be.Skipped == 0 {
+		return nil
+	}
+	return be
+}
+
+const defaultBulkConcurrency = 4
+
+var (
+	errNilBulkRequest   = errors.New("expecting a non-nil bulkRequest")
+	errMismatchedResume = errors.New("the resumed result is for a different number of operations")
+)
+
+func (breq *BulkRequest) Validate() error {
+	if breq == nil {
+		return errNilBulkRequest
+	}
+	for i, op := range breq.Operations {
+		if op == nil {
+			return fmt.Errorf("operation #%d is nil", i)
+		}
+	}
+	if breq.Resume != nil && len(breq.Resume.Items) != len(breq.Operations) {
+		return errMismatchedResume
+	}
+	return nil
+}
+
+// RunBulk runs the operations with a pool of workers and returns their
+// results in order. Once ctx is done no new operations are started.
+// Use BulkResult.Err to find out whether everything succeeded.
+func RunBulk(ctx context.Context, breq *BulkRequest) (*BulkResult, error) {
+	if err := breq.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{Items: make([]*BulkItemResult, len(breq.Operations))}
+	var pending []int
+	for i := range breq.Operations {
+		if breq.Resume != nil && breq.Resume.Items[i].Done() {
+			result.Items[i] = breq.Resume.Items[i]
+			continue
+		}
+		result.Items[i] = &BulkItemResult{Index: i, Skipped: true}
+		pending = append(pending, i)
+	}
+
+	concurrency := breq.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	stop := make(chan bool)
+	var stopOnce sync.Once
+	indicesChan := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indicesChan {
+				value, err := breq.Operations[i](ctx)
+				// Each worker writes to a distinct index so no lock is needed.
+				result.Items[i] = &BulkItemResult{Index: i, Value: value, Err: err}
+				if err != nil && breq.StopOnError {
+					stopOnce.Do(func() { close(stop) })
+				}
+			}
+		}()
+	}
+
+	var pacer <-chan time.Time
+	if breq.Pacing > 0 {
+		ticker := time.NewTicker(breq.Pacing)
+		defer ticker.Stop()
+		pacer = ticker.C
+	}
+
+dispatch:
+	for n, i := range pending {
+		// Checked first since select picks randomly among ready cases.
+		select {
+		case <-stop:
+			break dispatch
+		default:
+		}
+
+		if pacer != nil && n > 0 {
+			select {
+			case <-ctx.Done():
+				break dispatch
+			case <-stop:
+				break dispatch
+			case <-pacer:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case <-stop:
+			break dispatch
+		case indicesChan <- i:
+		}
+	}
+	close(indicesChan)
+	wg.Wait()
+
+	return result, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestRunBulk(t *testing.T) {
+	var calls int32
+	failing := map[int]bool{3: true, 7: true}
+	makeOps := func(failing map[int]bool) []asana.BulkOperation {
+		var ops []asana.BulkOperation
+		for i := 0; i < 10; i++ {
+			i := i
+			ops = append(ops, func(ctx context.Context) (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				if failing[i] {
+					return nil, errors.New("boom")
+				}
+				return i * i, nil
+			})
+		}
+		return ops
+	}
+
+	result, err := asana.RunBulk(context.Background(), &asana.BulkRequest{
+		Operations:  makeOps(failing),
+		Concurrency: 3,
+	})
+	if err != nil {
+		t.Fatalf("running: %v", err)
+	}
+	for i, item := range result.Items {
+		if item.Index != i {
+			t.Errorf("#%d: got index %d", i, item.Index)
+		}
+		if failing[i] != (item.Err != nil) {
+			t.Errorf("#%d: got err %v", i, item.Err)
+		}
+		if !failing[i] && item.Value.(int) != i*i {
+			t.Errorf("#%d: got value %v want %d", i, item.Value, i*i)
+		}
+	}
+	be, ok := result.Err().(*asana.BulkError)
+	if !ok || be.Failed != 2 || be.Total != 10 {
+		t.Fatalf("got bulk error %#v", result.Err())
+	}
+
+	// Resuming must only rerun the two failures.
+	atomic.StoreInt32(&calls, 0)
+	resumed, err := asana.RunBulk(context.Background(), &asana.BulkRequest{
+		Operations: makeOps(nil),
+		Resume:     result,
+	})
+	if err != nil {
+		t.Fatalf("resuming: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("resume: got %d calls want 2", got)
+	}
+	if err := resumed.Err(); err != nil {
+		t.Errorf("resume: got err %v", err)
+	}
+}
+
+func TestRunBulkStopOnError(t *testing.T) {
+	var ops []asana.BulkOperation
+	for i := 0; i < 20; i++ {
+		i := i
+		ops = append(ops, func(ctx context.Context) (interface{}, error) {
+			if i == 0 {
+				return nil, errors.New("boom")
+			}
+			return i, nil
+		})
+	}
+
+	result, err := asana.RunBulk(context.Background(), &asana.BulkRequest{
+		Operations:  ops,
+		Concurrency: 1,
+		StopOnError: true,
+	})
+	if err != nil {
+		t.Fatalf("running: %v", err)
+	}
+	if result.Items[0].Err == nil {
+		t.Errorf("expected the first operation to fail")
+	}
+	remaining := result.Remaining()
+	if len(remaining) < 18 {
+		t.Errorf("expected the run to stop early, only %d operations remain", len(remaining))
+	}
+}
+
+type bulkCtxKey struct{}
+
+func TestRunBulkPassesContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), bulkCtxKey{}, "run-1")
+	ops := []asana.BulkOperation{
+		func(ctx context.Context) (interface{}, error) {
+			return ctx.Value(bulkCtxKey{}), nil
+		},
+	}
+
+	result, err := asana.RunBulk(ctx, &asana.BulkRequest{Operations: ops})
+	if err != nil {
+		t.Fatalf("running: %v", err)
+	}
+	if g, w := result.Items[0].Value, "run-1"; g != w {
+		t.Errorf("got %v want %v", g, w)
+	}
+}
@@ -0,0 +1,701 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldTypeText      CustomFieldType = "text"
+	CustomFieldTypeNumber    CustomFieldType = "number"
+	CustomFieldTypeEnum      CustomFieldType = "enum"
+	CustomFieldTypeMultiEnum CustomFieldType = "multi_enum"
+	CustomFieldTypeDate      CustomFieldType = "date"
+	CustomFieldTypePeople    CustomFieldType = "people"
+)
+
+// CustomFieldFormat controls how number custom fields are displayed.
+// A currency custom field is a number custom field whose
+// format is FormatCurrency and whose CurrencyCode is set.
+type CustomFieldFormat string
+
+const (
+	FormatNone       CustomFieldFormat = "none"
+	FormatCurrency   CustomFieldFormat = "currency"
+	FormatPercentage CustomFieldFormat = "percentage"
+	FormatCustom     CustomFieldFormat = "custom"
+
+	// FormatDuration fields hold a number of minutes.
+	FormatDuration CustomFieldFormat = "duration"
+)
+
+type EnumOption struct {
+	ID      int64  `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Color   string `json:"color,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+type CustomFieldDate struct {
+	Date     *Date      `json:"date,omitempty"`
+	DateTime *time.Time `json:"date_time,omitempty"`
+}
+
+// CustomField is both the definition of a custom field in a
+// workspace and, when attached to a task, the value it holds.
+// Only the value field matching Type is ever set.
+type CustomField struct {
+	ID          int64           `json:"id,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Type        CustomFieldType `json:"resource_subtype,omitempty"`
+
+	Format       CustomFieldFormat `json:"format,omitempty"`
+	CurrencyCode string            `json:"currency_code,omitempty"`
+	Precision    int               `json:"precision,omitempty"`
+
+	EnumOptions []*EnumOption `json:"enum_options,omitempty"`
+
+	TextValue       *string              `json:"text_value,omitempty"`
+	NumberValue     *float64             `json:"number_value,omitempty"`
+	EnumValue       *EnumOption          `json:"enum_value,omitempty"`
+	MultiEnumValues []*EnumOption        `json:"multi_enum_values,omitempty"`
+	DateValue       *CustomFieldDate     `json:"date_value,omitempty"`
+	PeopleValue     []*NamedAndIDdEntity `json:"people_value,omitempty"`
+
+	// DisplayValue is a read-only, human readable
+	// rendition of whichever value is set.
+	DisplayValue string `json:"display_value,omitempty"`
+}
+
+type customFieldTypeError struct {
+	fieldID int64
+	got     CustomFieldType
+	want    CustomFieldType
+}
+
+func (e *customFieldTypeError) Error() string {
+	return fmt.Sprintf("custom field %d is of type %q not %q", e.fieldID, e.got, e.want)
+}
+
+func (cf *CustomField) checkType(want CustomFieldType) error {
+	if cf.Type != want {
+		return &customFieldTypeError{fieldID: cf.ID, got: cf.Type, want: want}
+	}
+	return nil
+}
+
+func (cf *CustomField) enumOptionByID(id int64) (*EnumOption, error) {
+	if len(cf.EnumOptions) == 0 {
+		// No definition was retrieved so trust the caller.
+		return &EnumOption{ID: id, Enabled: true}, nil
+	}
+	for _, opt := range cf.EnumOptions {
+		if opt != nil && opt.ID == id {
+			return opt, nil
+		}
+	}
+	return nil, fmt.Errorf("custom field %d has no enum option %d", cf.ID, id)
+}
+
+func (cf *CustomField) Text() (string, bool) {
+	if cf == nil || cf.Type != CustomFieldTypeText || cf.TextValue == nil {
+		return "", false
+	}
+	return *cf.TextValue, true
+}
+
+func (cf *CustomField) Number() (float64, bool) {
+	if cf == nil || cf.Type != CustomFieldTypeNumber || cf.NumberValue == nil {
+		return 0, false
+	}
+	return *cf.NumberValue, true
+}
+
+// Currency returns the amount held by a currency
+// custom field as well as its ISO 4217 currency code.
+func (cf *CustomField) Currency() (amount float64, currencyCode string, ok bool) {
+	if cf == nil || cf.Format != FormatCurrency {
+		return 0, "", false
+	}
+	amount, ok = cf.Number()
+	return amount, cf.CurrencyCode, ok
+}
+
+func (cf *CustomField) Enum() (*EnumOption, bool) {
+	if cf == nil || cf.Type != CustomFieldTypeEnum || cf.EnumValue == nil {
+		return nil, false
+	}
+	return cf.EnumValue, true
+}
+
+func (cf *CustomField) MultiEnum() ([]*EnumOption, bool) {
+	if cf == nil || cf.Type != CustomFieldTypeMultiEnum || len(cf.MultiEnumValues) == 0 {
+		return nil, false
+	}
+	return cf.MultiEnumValues, true
+}
+
+func (cf *CustomField) Date() (*CustomFieldDate, bool) {
+	if cf == nil || cf.Type != CustomFieldTypeDate || cf.DateValue == nil {
+		return nil, false
+	}
+	return cf.DateValue, true
+}
+
+func (cf *CustomField) People() ([]*NamedAndIDdEntity, bool) {
+	if cf == nil || cf.Type != CustomFieldTypePeople || len(cf.PeopleValue) == 0 {
+		return nil, false
+	}
+	return cf.PeopleValue, true
+}
+
+func (cf *CustomField) SetText(value string) error {
+	if err := cf.checkType(CustomFieldTypeText); err != nil {
+		return err
+	}
+	cf.TextValue = &value
+	return nil
+}
+
+func (cf *CustomField) SetNumber(value float64) error {
+	if err := cf.checkType(CustomFieldTypeNumber); err != nil {
+		return err
+	}
+	cf.NumberValue = &value
+	return nil
+}
+
+// SetEnum selects the enum option with the given ID. If the field's
+// definition includes its options, optionID must be one of them.
+func (cf *CustomField) SetEnum(optionID int64) error {
+	if err := cf.checkType(CustomFieldTypeEnum); err != nil {
+		return err
+	}
+	opt, err := cf.enumOptionByID(optionID)
+	if err != nil {
+		return err
+	}
+	cf.EnumValue = opt
+	return nil
+}
+
+func (cf *CustomField) SetMultiEnum(optionIDs ...int64) error {
+	if err := cf.checkType(CustomFieldTypeMultiEnum); err != nil {
+		return err
+	}
+	var opts []*EnumOption
+	for _, id := range optionIDs {
+		opt, err := cf.enumOptionByID(id)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, opt)
+	}
+	cf.MultiEnumValues = opts
+	return nil
+}
+
+func (cf *CustomField) SetDate(date *CustomFieldDate) error {
+	if err := cf.checkType(CustomFieldTypeDate); err != nil {
+		return err
+	}
+	cf.DateValue = date
+	return nil
+}
+
+func (cf *CustomField) SetPeople(userIDs ...int64) error {
+	if err := cf.checkType(CustomFieldTypePeople); err != nil {
+		return err
+	}
+	var people []*NamedAndIDdEntity
+	for _, id := range userIDs {
+		people = append(people, &NamedAndIDdEntity{ID: id})
+	}
+	cf.PeopleValue = people
+	return nil
+}
+
+// Clear unsets the value of the custom field so
+// that saving it will blank it out on the task.
+func (cf *CustomField) Clear() {
+	cf.TextValue = nil
+	cf.NumberValue = nil
+	cf.EnumValue = nil
+	cf.MultiEnumValues = nil
+	cf.DateValue = nil
+	cf.PeopleValue = nil
+}
+
+// jsonValue returns the value of the custom field
+// in the shape that the API expects when writing it.
+func (cf *CustomField) jsonValue() interface{} {
+	switch cf.Type {
+	case CustomFieldTypeText:
+		if cf.TextValue != nil {
+			return *cf.TextValue
+		}
+	case CustomFieldTypeNumber:
+		if cf.NumberValue != nil {
+			return *cf.NumberValue
+		}
+	case CustomFieldTypeEnum:
+		if cf.EnumValue != nil {
+			return strconv.FormatInt(cf.EnumValue.ID, 10)
+		}
+	case CustomFieldTypeMultiEnum:
+		ids := []string{}
+		for _, opt := range cf.MultiEnumValues {
+			ids = append(ids, strconv.FormatInt(opt.ID, 10))
+		}
+		return ids
+	case CustomFieldTypeDate:
+		if cf.DateValue != nil {
+			return cf.DateValue
+		}
+	case CustomFieldTypePeople:
+		ids := []string{}
+		for _, person := range cf.PeopleValue {
+			ids = append(ids, strconv.FormatInt(person.ID, 10))
+		}
+		return ids
+	}
+	return nil
+}
+
+// formValue is like jsonValue but for url-encoded bodies,
+// where lists are sent as comma separated values.
+func (cf *CustomField) formValue() string {
+	switch v := cf.jsonValue().(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return strings.Join(v, ",")
+	case *CustomFieldDate:
+		if v.DateTime != nil {
+			return v.DateTime.Format(time.RFC3339)
+		}
+		if v.Date == nil {
+			// An empty value clears the field.
+			return ""
+		}
+		return v.Date.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func (t *Task) CustomFieldByID(fieldID int64) *CustomField {
+	for _, cf := range t.CustomFields {
+		if cf != nil && cf.ID == fieldID {
+			return cf
+		}
+	}
+	return nil
+}
+
+// CustomFieldByName returns the first custom field
+// on the task whose name matches, ignoring case.
+func (t *Task) CustomFieldByName(name string) *CustomField {
+	for _, cf := range t.CustomFields {
+		if cf != nil && strings.EqualFold(cf.Name, name) {
+			return cf
+		}
+	}
+	return nil
+}
+
+func (t *Task) customFieldOrErr(fieldID int64) (*CustomField, error) {
+	if cf := t.CustomFieldByID(fieldID); cf != nil {
+		return cf, nil
+	}
+	return nil, fmt.Errorf("task %d has no custom field %d", t.ID, fieldID)
+}
+
+func (t *Task) TextValue(fieldID int64) (string, bool) {
+	return t.CustomFieldByID(fieldID).Text()
+}
+
+func (t *Task) NumberValue(fieldID int64) (float64, bool) {
+	return t.CustomFieldByID(fieldID).Number()
+}
+
+func (t *Task) CurrencyValue(fieldID int64) (amount float64, currencyCode string, ok bool) {
+	return t.CustomFieldByID(fieldID).Currency()
+}
+
+func (t *Task) EnumValue(fieldID int64) (*EnumOption, bool) {
+	return t.CustomFieldByID(fieldID).Enum()
+}
+
+func (t *Task) MultiEnumValues(fieldID int64) ([]*EnumOption, bool) {
+	return t.CustomFieldByID(fieldID).MultiEnum()
+}
+
+func (t *Task) DateValue(fieldID int64) (*CustomFieldDate, bool) {
+	return t.CustomFieldByID(fieldID).Date()
+}
+
+func (t *Task) PeopleValue(fieldID int64) ([]*NamedAndIDdEntity, bool) {
+	return t.CustomFieldByID(fieldID).People()
+}
+
+func (t *Task) SetTextValue(fieldID int64, value string) error {
+	cf, err := t.customFieldOrErr(fieldID)
+	if err != nil {
+		return err
+	}
+	return cf.SetText(value)
+}
+
+func (t *Task) SetNumberValue(fieldID int64, value float64) error {
+	cf, err := t.customFieldOrErr(fieldID)
+	if err != nil {
+		return err
+	}
+	return cf.SetNumber(value)
+}
+
+func (t *Task) SetEnumValue(fieldID, optionID int64) error {
+	cf, err := t.customFieldOrErr(fieldID)
+	if err != nil {
+		return err
+	}
+	return cf.SetEnum(optionID)
+}
+
+func (t *Task) SetMultiEnumValues(fieldID int64, optionIDs ...int64) error {
+	cf, err := t.customFieldOrErr(fieldID)
+	if err != nil {
+		return err
+	}
+	return cf.SetMultiEnum(optionIDs...)
+}
+
+func (t *Task) SetDateValue(fieldID int64, date *CustomFieldDate) error {
+	cf, err := t.customFieldOrErr(fieldID)
+	if err != nil {
+		return err
+	}
+	return cf.SetDate(date)
+}
+
+func (t *Task) SetPeopleValue(fieldID int64, userIDs ...int64) error {
+	cf, err := t.customFieldOrErr(fieldID)
+	if err != nil {
+		return err
+	}
+	return cf.SetPeople(userIDs...)
+}
+
+var errNoCustomFields = errors.New("expecting at least one custom field")
+
+// UpdateTaskCustomFields saves the values of the passed in custom
+// fields onto the task. Fields whose values were cleared are blanked out.
+func (c *Client) UpdateTaskCustomFields(taskID string, fields ...*CustomField) (*Task, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, errEmptyTaskID
+	}
+	if len(fields) == 0 {
+		return nil, errNoCustomFields
+	}
+
+	values := make(map[string]interface{})
+	for _, cf := range fields {
+		if cf == nil {
+			continue
+		}
+		values[strconv.FormatInt(cf.ID, 10)] = cf.jsonValue()
+	}
+	data := map[string]interface{}{"custom_fields": values}
+
+	path := fmt.Sprintf("/tasks/%s", taskID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("PUT", path, data)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTaskFromData(slurp)
+}
+
+type EnumOptionRequest struct {
+	CustomFieldID string `json:"-"`
+	EnumOptionID  string `json:"-"`
+
+	Name    string `json:"name,omitempty"`
+	Color   string `json:"color,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"`
+
+	// InsertBefore and InsertAfter position the option relative
+	// to an existing one; by default it is added to the end.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+type CustomFieldRequest struct {
+	CustomFieldID string `json:"-"`
+
+	Workspace   string          `json:"workspace,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Type        CustomFieldType `json:"resource_subtype,omitempty"`
+
+	Format       CustomFieldFormat `json:"format,omitempty"`
+	CurrencyCode string            `json:"currency_code,omitempty"`
+	Precision    *int              `json:"precision,omitempty"`
+
+	// EnumOptions can only be set at creation, afterwards
+	// use CreateEnumOption and UpdateEnumOption.
+	EnumOptions []*EnumOptionRequest `json:"enum_options,omitempty"`
+}
+
+var (
+	errNilCustomFieldRequest = errors.New("expecting a non-nil customFieldRequest")
+	errNilEnumOptionRequest  = errors.New("expecting a non-nil enumOptionRequest")
+
+	errEmptyCustomFieldID   = errors.New("expecting a non-empty customFieldID")
+	errEmptyCustomFieldName = errors.New("expecting a non-empty name")
+	errEmptyCustomFieldType = errors.New("expecting a non-empty custom field type")
+	errEmptyEnumOptionID    = errors.New("expecting a non-empty enumOptionID")
+
+	errBothInsertBeforeAndAfter = errors.New("only one of InsertBefore and InsertAfter can be set")
+)
+
+func (cfr *CustomFieldRequest) Validate() error {
+	if cfr == nil {
+		return errNilCustomFieldRequest
+	}
+	if strings.TrimSpace(cfr.Workspace) == "" {
+		return errEmptyWorkspace
+	}
+	if strings.TrimSpace(cfr.Name) == "" {
+		return errEmptyCustomFieldName
+	}
+	if cfr.Type == "" {
+		return errEmptyCustomFieldType
+	}
+	return nil
+}
+
+type customFieldWrap struct {
+	CustomField *CustomField `json:"data"`
+}
+
+func parseOutCustomFieldFromData(blob []byte) (*CustomField, error) {
+	cfw := new(customFieldWrap)
+	if err := json.Unmarshal(blob, cfw); err != nil {
+		return nil, err
+	}
+	return cfw.CustomField, nil
+}
+
+func (c *Client) CreateCustomField(cfr *CustomFieldRequest) (*CustomField, error) {
+	if err := cfr.Validate(); err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", "/custom_fields", cfr)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutCustomFieldFromData(slurp)
+}
+
+func (c *Client) FindCustomFieldByID(customFieldID string) (*CustomField, error) {
+	customFieldID = strings.TrimSpace(customFieldID)
+	if customFieldID == "" {
+		return nil, errEmptyCustomFieldID
+	}
+	fullURL := fmt.Sprintf("%s/custom_fields/%s", baseURL, customFieldID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutCustomFieldFromData(slurp)
+}
+
+// UpdateCustomField changes the definition of a custom field.
+// Its type and workspace cannot be changed once it has been created.
+func (c *Client) UpdateCustomField(cfr *CustomFieldRequest) (*CustomField, error) {
+	if cfr == nil {
+		return nil, errNilCustomFieldRequest
+	}
+	customFieldID := strings.TrimSpace(cfr.CustomFieldID)
+	if customFieldID == "" {
+		return nil, errEmptyCustomFieldID
+	}
+	if cfr.Workspace != "" {
+		return nil, errImmutableWorkspace
+	}
+	path := fmt.Sprintf("/custom_fields/%s", customFieldID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("PUT", path, cfr)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutCustomFieldFromData(slurp)
+}
+
+func (c *Client) DeleteCustomField(customFieldID string) error {
+	customFieldID = strings.TrimSpace(customFieldID)
+	if customFieldID == "" {
+		return errEmptyCustomFieldID
+	}
+	fullURL := fmt.Sprintf("%s/custom_fields/%s", baseURL, customFieldID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type CustomFieldsPage struct {
+	CustomFields []*CustomField `json:"data"`
+	Err          error
+}
+
+type customFieldsPager struct {
+	CustomFieldsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListCustomFieldsForWorkspace(workspaceID string) (pagesChan chan *CustomFieldsPage, cancelChan chan<- bool, err error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *CustomFieldsPage)
+
+	go c.paginate(fmt.Sprintf("/workspaces/%s/custom_fields", workspaceID), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(customFieldsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.CustomFieldsPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
+
+type enumOptionWrap struct {
+	EnumOption *EnumOption `json:"data"`
+}
+
+func parseOutEnumOptionFromData(blob []byte) (*EnumOption, error) {
+	eow := new(enumOptionWrap)
+	if err := json.Unmarshal(blob, eow); err != nil {
+		return nil, err
+	}
+	return eow.EnumOption, nil
+}
+
+// CreateEnumOption adds an option to an enum or multi_enum custom field.
+// Set InsertBefore or InsertAfter to control where it is placed.
+func (c *Client) CreateEnumOption(eor *EnumOptionRequest) (*EnumOption, error) {
+	if eor == nil {
+		return nil, errNilEnumOptionRequest
+	}
+	customFieldID := strings.TrimSpace(eor.CustomFieldID)
+	if customFieldID == "" {
+		return nil, errEmptyCustomFieldID
+	}
+	if strings.TrimSpace(eor.Name) == "" {
+		return nil, errEmptyCustomFieldName
+	}
+	if eor.InsertBefore != "" && eor.InsertAfter != "" {
+		return nil, errBothInsertBeforeAndAfter
+	}
+	path := fmt.Sprintf("/custom_fields/%s/enum_options", customFieldID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, eor)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutEnumOptionFromData(slurp)
+}
+
+// UpdateEnumOption renames, recolors, enables or disables an enum option.
+// Options can't be deleted, only disabled by setting Enabled to false.
+func (c *Client) UpdateEnumOption(eor *EnumOptionRequest) (*EnumOption, error) {
+	if eor == nil {
+		return nil, errNilEnumOptionRequest
+	}
+	enumOptionID := strings.TrimSpace(eor.EnumOptionID)
+	if enumOptionID == "" {
+		return nil, errEmptyEnumOptionID
+	}
+	path := fmt.Sprintf("/enum_options/%s", enumOptionID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("PUT", path, eor)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutEnumOptionFromData(slurp)
+}
+
+type enumOptionInsertion struct {
+	EnumOption   string `json:"enum_option"`
+	BeforeOption string `json:"before_enum_option,omitempty"`
+	AfterOption  string `json:"after_enum_option,omitempty"`
+}
+
+// ReorderEnumOption moves an existing enum option of a custom field to
+// just before InsertBefore or just after InsertAfter, whichever is set.
+func (c *Client) ReorderEnumOption(eor *EnumOptionRequest) (*EnumOption, error) {
+	if eor == nil {
+		return nil, errNilEnumOptionRequest
+	}
+	customFieldID := strings.TrimSpace(eor.CustomFieldID)
+	if customFieldID == "" {
+		return nil, errEmptyCustomFieldID
+	}
+	enumOptionID := strings.TrimSpace(eor.EnumOptionID)
+	if enumOptionID == "" {
+		return nil, errEmptyEnumOptionID
+	}
+	if eor.InsertBefore != "" && eor.InsertAfter != "" {
+		return nil, errBothInsertBeforeAndAfter
+	}
+
+	insertion := &enumOptionInsertion{
+		EnumOption:   enumOptionID,
+		BeforeOption: eor.InsertBefore,
+		AfterOption:  eor.InsertAfter,
+	}
+	path := fmt.Sprintf("/custom_fields/%s/enum_options/insert", customFieldID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, insertion)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutEnumOptionFromData(slurp)
+}
@@ -0,0 +1,174 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const taskWithCustomFieldsJSON = `{
+  "id": 1001,
+  "name": "Ship it",
+  "custom_fields": [
+    {"id": 1, "name": "Notes", "resource_subtype": "text", "text_value": "hello"},
+    {"id": 2, "name": "Budget", "resource_subtype": "number", "format": "currency",
+     "currency_code": "EUR", "number_value": 42.5},
+    {"id": 3, "name": "Priority", "resource_subtype": "enum",
+     "enum_options": [{"id": 31, "name": "Low", "enabled": true}, {"id": 32, "name": "High", "enabled": true}],
+     "enum_value": {"id": 31, "name": "Low", "enabled": true}},
+    {"id": 4, "name": "Reviewers", "resource_subtype": "people",
+     "people_value": [{"id": 7, "name": "Ada"}]}
+  ]
+}`
+
+func TestTaskCustomFieldGetters(t *testing.T) {
+	task := new(asana.Task)
+	if err := json.Unmarshal([]byte(taskWithCustomFieldsJSON), task); err != nil {
+		t.Fatalf("unmarshaling task: %v", err)
+	}
+
+	if got, ok := task.TextValue(1); !ok || got != "hello" {
+		t.Errorf("text: got (%q, %v) want (\"hello\", true)", got, ok)
+	}
+	if amount, code, ok := task.CurrencyValue(2); !ok || amount != 42.5 || code != "EUR" {
+		t.Errorf("currency: got (%v, %q, %v)", amount, code, ok)
+	}
+	if opt, ok := task.EnumValue(3); !ok || opt.Name != "Low" {
+		t.Errorf("enum: got (%#v, %v)", opt, ok)
+	}
+	if people, ok := task.PeopleValue(4); !ok || len(people) != 1 || people[0].ID != 7 {
+		t.Errorf("people: got (%#v, %v)", people, ok)
+	}
+
+	// Empty values, like unset ones, must not be reported as present.
+	empty := new(asana.Task)
+	blob := `{"custom_fields": [
+	  {"id": 5, "resource_subtype": "multi_enum", "multi_enum_values": []},
+	  {"id": 6, "resource_subtype": "people", "people_value": null},
+	  {"id": 7, "resource_subtype": "multi_enum", "multi_enum_values": [{"id": 71, "name": "Web"}]}
+	]}`
+	if err := json.Unmarshal([]byte(blob), empty); err != nil {
+		t.Fatalf("unmarshaling task: %v", err)
+	}
+	if values, ok := empty.MultiEnumValues(5); ok {
+		t.Errorf("empty multi enum: got (%#v, true)", values)
+	}
+	if people, ok := empty.PeopleValue(6); ok {
+		t.Errorf("absent people: got (%#v, true)", people)
+	}
+	if values, ok := empty.MultiEnumValues(7); !ok || len(values) != 1 || values[0].ID != 71 {
+		t.Errorf("multi enum: got (%#v, %v)", values, ok)
+	}
+
+	// Mismatched types must not be reported as present.
+	if _, ok := task.NumberValue(1); ok {
+		t.Errorf("number getter on a text field should fail")
+	}
+	if _, ok := task.TextValue(99); ok {
+		t.Errorf("text getter on a missing field should fail")
+	}
+	if cf := task.CustomFieldByName("priority"); cf == nil || cf.ID != 3 {
+		t.Errorf("by name: got %#v", cf)
+	}
+}
+
+func TestTaskCustomFieldSetters(t *testing.T) {
+	task := new(asana.Task)
+	if err := json.Unmarshal([]byte(taskWithCustomFieldsJSON), task); err != nil {
+		t.Fatalf("unmarshaling task: %v", err)
+	}
+
+	tests := [...]struct {
+		name    string
+		set     func() error
+		wantErr bool
+	}{
+		0: {name: "text", set: func() error { return task.SetTextValue(1, "bye") }},
+		1: {name: "number", set: func() error { return task.SetNumberValue(2, 10) }},
+		2: {name: "enum", set: func() error { return task.SetEnumValue(3, 32) }},
+		3: {name: "unknown enum option", set: func() error { return task.SetEnumValue(3, 99) }, wantErr: true},
+		4: {name: "type mismatch", set: func() error { return task.SetNumberValue(1, 3) }, wantErr: true},
+		5: {name: "missing field", set: func() error { return task.SetTextValue(99, "x") }, wantErr: true},
+		6: {name: "people", set: func() error { return task.SetPeopleValue(4, 8, 9) }},
+	}
+
+	for i, tt := range tests {
+		err := tt.set()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d %s: wanted non-nil error", i, tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d %s: got err: %v", i, tt.name, err)
+		}
+	}
+
+	if got, _ := task.TextValue(1); got != "bye" {
+		t.Errorf("text: got %q want \"bye\"", got)
+	}
+	if got, _ := task.NumberValue(2); got != 10 {
+		t.Errorf("number: got %v want 10", got)
+	}
+	if opt, _ := task.EnumValue(3); opt == nil || opt.Name != "High" {
+		t.Errorf("enum: got %#v want the \"High\" option", opt)
+	}
+	if people, _ := task.PeopleValue(4); len(people) != 2 {
+		t.Errorf("people: got %d people want 2", len(people))
+	}
+}
+
+func TestCreateTaskWithClearedDateCustomField(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &scriptedBackend{
+		resps: []scriptedResp{{code: http.StatusCreated, body: `{"data": {"id": 1001}}`}},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	cf := &asana.CustomField{ID: 5, Type: asana.CustomFieldTypeDate}
+	if err := cf.SetDate(&asana.CustomFieldDate{}); err != nil {
+		t.Fatalf("setting the date: %v", err)
+	}
+	if _, err := client.CreateTask(&asana.TaskRequest{
+		Name:         "Ship it",
+		Workspace:    "14916",
+		CustomFields: []*asana.CustomField{cf},
+	}); err != nil {
+		t.Fatalf("creating the task: %v", err)
+	}
+
+	if len(be.reqs) != 1 {
+		t.Fatalf("got %d requests want 1", len(be.reqs))
+	}
+	blob, _ := ioutil.ReadAll(be.reqs[0].Body)
+	form, err := url.ParseQuery(string(blob))
+	if err != nil {
+		t.Fatalf("parsing the form: %v", err)
+	}
+	values, ok := form["custom_fields[5]"]
+	if !ok || len(values) != 1 || values[0] != "" {
+		t.Errorf("got custom_fields[5]=%q want a single empty value", values)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/orijtech/otils"
+)
+
+// CustomFieldSetting is the association of a custom
+// field definition with a project or a portfolio.
+type CustomFieldSetting struct {
+	ID int64 `json:"id,omitempty"`
+
+	CustomField *CustomField `json:"custom_field,omitempty"`
+
+	// Parent is the project or portfolio
+	// that the custom field is attached to.
+	Parent *NamedAndIDdEntity `json:"parent,omitempty"`
+
+	// IsImportant custom fields are
+	// shown in task list views.
+	IsImportant bool `json:"is_important"`
+}
+
+type CustomFieldSettingRequest struct {
+	// Exactly one of ProjectID and PortfolioID must be set.
+	ProjectID   string `json:"-"`
+	PortfolioID string `json:"-"`
+
+	CustomFieldID string `json:"custom_field"`
+	IsImportant   bool   `json:"is_important,omitempty"`
+
+	// InsertBefore and InsertAfter are IDs of custom fields already
+	// set on the parent; by default the field is added to the end.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+var (
+	errNilCustomFieldSettingRequest = errors.New("expecting a non-nil customFieldSettingRequest")
+	errEmptyPortfolioID             = errors.New("expecting a non-empty portfolioID")
+
+	errBothProjectAndPortfolio = errors.New("only one of ProjectID and PortfolioID can be set")
+)
+
+func (cfsr *CustomFieldSettingRequest) Validate() error {
+	if cfsr == nil {
+		return errNilCustomFieldSettingRequest
+	}
+	projectID := strings.TrimSpace(cfsr.ProjectID)
+	portfolioID := strings.TrimSpace(cfsr.PortfolioID)
+	if projectID == "" && portfolioID == "" {
+		return errEmptyProjectID
+	}
+	if projectID != "" && portfolioID != "" {
+		return errBothProjectAndPortfolio
+	}
+	if strings.TrimSpace(cfsr.CustomFieldID) == "" {
+		return errEmptyCustomFieldID
+	}
+	if cfsr.InsertBefore != "" && cfsr.InsertAfter != "" {
+		return errBothInsertBeforeAndAfter
+	}
+	return nil
+}
+
+func (cfsr *CustomFieldSettingRequest) parentPath() string {
+	if portfolioID := strings.TrimSpace(cfsr.PortfolioID); portfolioID != "" {
+		return fmt.Sprintf("/portfolios/%s", portfolioID)
+	}
+	return fmt.Sprintf("/projects/%s", strings.TrimSpace(cfsr.ProjectID))
+}
+
+type customFieldSettingWrap struct {
+	CustomFieldSetting *CustomFieldSetting `json:"data"`
+}
+
+// AddCustomFieldSetting attaches a custom field to a project or a portfolio
+// so that its tasks or items can hold values for that custom field.
+func (c *Client) AddCustomFieldSetting(cfsr *CustomFieldSettingRequest) (*CustomFieldSetting, error) {
+	if err := cfsr.Validate(); err != nil {
+		return nil, err
+	}
+
+	qs, err := otils.ToURLValues(cfsr)
+	if err != nil {
+		return nil, err
+	}
+
+	fullURL := fmt.Sprintf("%s%s/addCustomFieldSetting", baseURL, cfsr.parentPath())
+	req, err := http.NewRequest("POST", fullURL, strings.NewReader(qs.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cfsw := new(customFieldSettingWrap)
+	if err := json.Unmarshal(slurp, cfsw); err != nil {
+		return nil, err
+	}
+	return cfsw.CustomFieldSetting, nil
+}
+
+// RemoveCustomFieldSetting detaches a custom field from a project or a
+// portfolio. Only CustomFieldID and the parent's ID are used.
+func (c *Client) RemoveCustomFieldSetting(cfsr *CustomFieldSettingRequest) error {
+	if err := cfsr.Validate(); err != nil {
+		return err
+	}
+
+	qs, err := otils.ToURLValues(&CustomFieldSettingRequest{CustomFieldID: cfsr.CustomFieldID})
+	if err != nil {
+		return err
+	}
+
+	fullURL := fmt.Sprintf("%s%s/removeCustomFieldSetting", baseURL, cfsr.parentPath())
+	req, err := http.NewRequest("POST", fullURL, strings.NewReader(qs.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type CustomFieldSettingsPage struct {
+	CustomFieldSettings []*CustomFieldSetting `json:"data"`
+	Err                 error
+}
+
+type customFieldSettingsPager struct {
+	CustomFieldSettingsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListCustomFieldSettingsForProject lists the custom fields
+// attached to a project, in the order that they are displayed.
+func (c *Client) ListCustomFieldSettingsForProject(projectID string) (pagesChan chan *CustomFieldSettingsPage, cancelChan chan<- bool, err error) {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return nil, nil, errEmptyProjectID
+	}
+	path := fmt.Sprintf("/projects/%s/custom_field_settings", projectID)
+	return c.pageForCustomFieldSettings(path)
+}
+
+// ListCustomFieldSettingsForPortfolio lists the custom fields
+// attached to a portfolio, in the order that they are displayed.
+func (c *Client) ListCustomFieldSettingsForPortfolio(portfolioID string) (pagesChan chan *CustomFieldSettingsPage, cancelChan chan<- bool, err error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, nil, errEmptyPortfolioID
+	}
+	path := fmt.Sprintf("/portfolios/%s/custom_field_settings", portfolioID)
+	return c.pageForCustomFieldSettings(path)
+}
+
+func (c *Client) pageForCustomFieldSettings(path string) (pagesChan chan *CustomFieldSettingsPage, cancelChan chan<- bool, err error) {
+	pagesChan = make(chan *CustomFieldSettingsPage)
+	cancel := make(chan bool, 1)
+
+	go c.paginate(path, pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(customFieldSettingsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.CustomFieldSettingsPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestAddCustomFieldSetting(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		req      *asana.CustomFieldSettingRequest
+		wantErr  bool
+		wantPath string
+		wantForm url.Values
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.CustomFieldSettingRequest{CustomFieldID: "3"}, wantErr: true},
+		2: {req: &asana.CustomFieldSettingRequest{ProjectID: "9", PortfolioID: "31", CustomFieldID: "3"}, wantErr: true},
+		3: {req: &asana.CustomFieldSettingRequest{ProjectID: "9"}, wantErr: true},
+		4: {
+			req:     &asana.CustomFieldSettingRequest{ProjectID: "9", CustomFieldID: "3", InsertBefore: "1", InsertAfter: "2"},
+			wantErr: true,
+		},
+		5: {
+			req:      &asana.CustomFieldSettingRequest{ProjectID: " 9 ", CustomFieldID: "3", IsImportant: true},
+			wantPath: "/api/1.0/projects/9/addCustomFieldSetting",
+			wantForm: url.Values{"custom_field": {"3"}, "is_important": {"true"}},
+		},
+		6: {
+			req:      &asana.CustomFieldSettingRequest{PortfolioID: "31", CustomFieldID: "3", InsertAfter: "2"},
+			wantPath: "/api/1.0/portfolios/31/addCustomFieldSetting",
+			wantForm: url.Values{"custom_field": {"3"}, "insert_after": {"2"}},
+		},
+	}
+
+	settingJSON := `{"data": {"id": 60, "is_important": true,
+	  "custom_field": {"id": 3, "name": "Priority"}, "parent": {"id": 9, "name": "Release"}}}`
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: settingJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		setting, err := client.AddCustomFieldSetting(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			if len(be.reqs) != 0 {
+				t.Errorf("#%d: invalid request was sent", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "POST "+tt.wantPath; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		if err := req.ParseForm(); err != nil {
+			t.Errorf("#%d: parsing the form: %v", i, err)
+			continue
+		}
+		for key, want := range tt.wantForm {
+			if got := req.PostForm.Get(key); got != want[0] {
+				t.Errorf("#%d: %q: got %q want %q", i, key, got, want[0])
+			}
+		}
+		if setting.ID != 60 || setting.CustomField == nil || setting.CustomField.ID != 3 || !setting.IsImportant {
+			t.Errorf("#%d: unexpected setting: %+v", i, setting)
+		}
+	}
+}
+
+func TestRemoveCustomFieldSetting(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: `{"data": {}}`}}}
+	client.SetHTTPRoundTripper(be)
+
+	if err := client.RemoveCustomFieldSetting(&asana.CustomFieldSettingRequest{CustomFieldID: "3"}); err == nil {
+		t.Errorf("expected an error without a parent")
+	}
+
+	// Only the custom field is sent.
+	err = client.RemoveCustomFieldSetting(&asana.CustomFieldSettingRequest{
+		PortfolioID: "31", CustomFieldID: "3", IsImportant: true, InsertAfter: "2",
+	})
+	if err != nil {
+		t.Fatalf("removing: %v", err)
+	}
+	req := be.reqs[0]
+	if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/portfolios/31/removeCustomFieldSetting"; g != w {
+		t.Errorf("got %q want %q", g, w)
+	}
+	if err := req.ParseForm(); err != nil {
+		t.Fatalf("parsing the form: %v", err)
+	}
+	if g, w := req.PostForm.Encode(), "custom_field=3"; g != w {
+		t.Errorf("got form %q want %q", g, w)
+	}
+}
+
+func TestListCustomFieldSettings(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	if _, _, err := client.ListCustomFieldSettingsForProject(" "); err == nil {
+		t.Errorf("expected an error for an empty projectID")
+	}
+	if _, _, err := client.ListCustomFieldSettingsForPortfolio(""); err == nil {
+		t.Errorf("expected an error for an empty portfolioID")
+	}
+
+	tests := [...]struct {
+		projectID   string
+		portfolioID string
+		wantPath    string
+	}{
+		0: {projectID: "9", wantPath: "/api/1.0/projects/9/custom_field_settings"},
+		1: {portfolioID: "31", wantPath: "/api/1.0/portfolios/31/custom_field_settings"},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{
+			resps: []scriptedResp{
+				{code: http.StatusOK, body: `{"data": [{"id": 60, "custom_field": {"id": 3}}],
+				  "next_page": {"offset": "a", "path": "/custom_field_settings?offset=a"}}`},
+				{code: http.StatusOK, body: `{"data": [{"id": 61, "custom_field": {"id": 4}}]}`},
+			},
+		}
+		client.SetHTTPRoundTripper(be)
+
+		var pagesChan chan *asana.CustomFieldSettingsPage
+		if tt.portfolioID != "" {
+			pagesChan, _, err = client.ListCustomFieldSettingsForPortfolio(tt.portfolioID)
+		} else {
+			pagesChan, _, err = client.ListCustomFieldSettingsForProject(tt.projectID)
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		var ids []int64
+		for page := range pagesChan {
+			if page.Err != nil {
+				t.Errorf("#%d: unexpected page error: %v", i, page.Err)
+				continue
+			}
+			for _, setting := range page.CustomFieldSettings {
+				ids = append(ids, setting.CustomField.ID)
+			}
+		}
+		if len(ids) != 2 || ids[0] != 3 || ids[1] != 4 {
+			t.Errorf("#%d: got custom fields %v want [3 4]", i, ids)
+		}
+		if g, w := be.reqs[0].URL.Path, tt.wantPath; g != w {
+			t.Errorf("#%d: got path %q want %q", i, g, w)
+		}
+		if g, w := be.reqs[1].URL.Query().Get("offset"), "a"; g != w {
+			t.Errorf("#%d: got offset %q want %q", i, g, w)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Date is a calendar date without a time of day or a location, as used
+// by due_on and start_on. The zero Date stands for no date: it marshals
+// to null, which clears the date when sent in a JSON request body.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+var (
+	_ json.Marshaler   = Date{}
+	_ json.Unmarshaler = (*Date)(nil)
+)
+
+// NewDate normalizes its arguments the way time.Date does, so
+// that e.g. NewDate(2017, time.February, 30) is March 2nd 2017.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the date of t in t's location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// Today returns the current date in loc.
+func Today(loc *time.Location) Date {
+	return DateOf(time.Now().In(loc))
+}
+
+// ParseDate parses dates in the YYYY-MM-DD form.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, err
+	}
+	return DateOf(t), nil
+}
+
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// String formats the date as YYYY-MM-DD, or "" for the zero Date.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// In returns the time at which the date starts in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.Year, d.Month, d.Day+n)
+}
+
+// DaysSince returns the number of days from other to d,
+// which is negative if d is the earlier of the two.
+func (d Date) DaysSince(other Date) int {
+	// NewDate brings out of range months and days back into range.
+	d, other = NewDate(d.Year, d.Month, d.Day), NewDate(other.Year, other.Month, other.Day)
+	return d.daysFromCivil() - other.daysFromCivil()
+}
+
+// daysFromCivil returns the number of days since 1970-01-01, computed
+// from the calendar rather than through time.Duration which overflows
+// after about 292 years.
+func (d Date) daysFromCivil() int {
+	y, m := d.Year, int(d.Month)
+	if m <= 2 {
+		y--
+	}
+	era := y / 400
+	if y < 0 && y%400 != 0 {
+		era--
+	}
+	yoe := y - era*400
+	mp := (m + 9) % 12
+	doy := (153*mp+2)/5 + d.Day - 1
+	doe := yoe*365 + yoe/4 - yoe/100 + doy
+	return era*146097 + doe - 719468
+}
+
+func (d Date) Before(other Date) bool {
+	return d.compare(other) < 0
+}
+
+func (d Date) After(other Date) bool {
+	return d.compare(other) > 0
+}
+
+func (d Date) compare(other Date) int {
+	switch {
+	case d.Year != other.Year:
+		return d.Year - other.Year
+	case d.Month != other.Month:
+		return int(d.Month - other.Month)
+	default:
+		return d.Day - other.Day
+	}
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+var errInvalidDate = errors.New("expecting a date as YYYY-MM-DD")
+
+func (d *Date) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*d = Date{}
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return errInvalidDate
+	}
+	if str == "" {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(str)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// DateRange is the span of days from Start to Due inclusive.
+// Either end can be the zero Date if it isn't set.
+type DateRange struct {
+	Start Date
+	Due   Date
+}
+
+// Contains reports whether d falls within the range. Open ended
+// ranges extend indefinitely while an empty range contains nothing.
+func (dr DateRange) Contains(d Date) bool {
+	if dr.Start.IsZero() && dr.Due.IsZero() {
+		return false
+	}
+	if !dr.Start.IsZero() && d.Before(dr.Start) {
+		return false
+	}
+	if !dr.Due.IsZero() && d.After(dr.Due) {
+		return false
+	}
+	return true
+}
+
+var errStartWithoutDue = errors.New("a start date requires a due date")
+
+// Validate checks that the range is acceptable to Asana, which requires a
+// due date whenever there's a start date and the former to be the later.
+func (dr DateRange) Validate() error {
+	if dr.Start.IsZero() {
+		return nil
+	}
+	if dr.Due.IsZero() {
+		return errStartWithoutDue
+	}
+	if dr.Start.After(dr.Due) {
+		return fmt.Errorf("start date %s is after due date %s", dr.Start, dr.Due)
+	}
+	return nil
+}
+
+func dateOrZero(d *Date) Date {
+	if d == nil {
+		return Date{}
+	}
+	return *d
+}
+
+// dueDate is the date of dueOn, or else that of dueAt in its location.
+func dueDate(dueOn *Date, dueAt *time.Time) Date {
+	if dueOn == nil && dueAt != nil {
+		return DateOf(*dueAt)
+	}
+	return dateOrZero(dueOn)
+}
+
+// Dates returns the span of the task from its StartOn
+// to its DueOn, or the date of DueAt if that's unset.
+func (t *Task) Dates() DateRange {
+	return DateRange{Start: dateOrZero(t.StartOn), Due: dueDate(t.DueOn, t.DueAt)}
+}
+
+// SetDates sets StartOn and DueOn to the range's, after validating it.
+// Zero ends are left unset.
+func (treq *TaskRequest) SetDates(dr DateRange) error {
+	if err := dr.Validate(); err != nil {
+		return err
+	}
+	treq.StartOn, treq.DueOn = nil, nil
+	if !dr.Start.IsZero() {
+		start := dr.Start
+		treq.StartOn = &start
+	}
+	if !dr.Due.IsZero() {
+		due := dr.Due
+		treq.DueOn = &due
+	}
+	return nil
+}
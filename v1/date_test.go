@@ -0,0 +1,173 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestDateJSON(t *testing.T) {
+	type dated struct {
+		DueOn   asana.Date  `json:"due_on"`
+		StartOn *asana.Date `json:"start_on,omitempty"`
+	}
+
+	march5 := asana.NewDate(2017, time.March, 5)
+	blob, err := json.Marshal(&dated{DueOn: march5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(blob), `{"due_on":"2017-03-05"}`; got != want {
+		t.Errorf("marshal: got %s want %s", got, want)
+	}
+
+	blob, _ = json.Marshal(&dated{StartOn: &asana.Date{}})
+	if got, want := string(blob), `{"due_on":null,"start_on":null}`; got != want {
+		t.Errorf("marshal zero: got %s want %s", got, want)
+	}
+
+	tests := [...]struct {
+		in      string
+		want    asana.Date
+		wantErr bool
+	}{
+		0: {in: `{"due_on":"2017-03-05"}`, want: march5},
+		1: {in: `{"due_on":null}`},
+		2: {in: `{"due_on":""}`},
+		3: {in: `{"due_on":"2017-3-5"}`, wantErr: true},
+		4: {in: `{"due_on":20170305}`, wantErr: true},
+	}
+	for i, tt := range tests {
+		d := new(dated)
+		err := json.Unmarshal([]byte(tt.in), d)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if d.DueOn != tt.want {
+			t.Errorf("#%d: got %v want %v", i, d.DueOn, tt.want)
+		}
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	start := asana.NewDate(2017, time.December, 30)
+	due := start.AddDays(5)
+	if got, want := due.String(), "2018-01-04"; got != want {
+		t.Errorf("AddDays: got %s want %s", got, want)
+	}
+	if got := due.DaysSince(start); got != 5 {
+		t.Errorf("DaysSince: got %d want 5", got)
+	}
+	for i, tt := range [...]struct {
+		from, to asana.Date
+		want     int
+	}{
+		0: {from: asana.NewDate(1970, time.January, 1), to: asana.NewDate(2000, time.March, 1), want: 11017},
+		// Spans too long for a time.Duration.
+		1: {from: asana.NewDate(1, time.January, 1), to: asana.NewDate(2017, time.January, 1), want: 736329},
+		2: {from: asana.NewDate(2017, time.January, 1), to: asana.NewDate(1600, time.March, 1), want: -152247},
+		3: {from: asana.NewDate(-400, time.January, 1), to: asana.NewDate(0, time.January, 1), want: 146097},
+	} {
+		if got := tt.to.DaysSince(tt.from); got != tt.want {
+			t.Errorf("DaysSince #%d: got %d want %d", i, got, tt.want)
+		}
+	}
+
+	dr := asana.DateRange{Start: start, Due: due}
+	if err := dr.Validate(); err != nil {
+		t.Errorf("unexpected err: %v", err)
+	}
+	for d, want := range map[asana.Date]bool{
+		start.AddDays(-1): false,
+		start:             true,
+		due:               true,
+		due.AddDays(1):    false,
+	} {
+		if got := dr.Contains(d); got != want {
+			t.Errorf("%s: got contained=%v want %v", d, got, want)
+		}
+	}
+
+	if err := (asana.DateRange{Start: due, Due: start}).Validate(); err == nil {
+		t.Errorf("expected an error for a start after the due date")
+	}
+	if err := (asana.DateRange{Start: start}).Validate(); err == nil {
+		t.Errorf("expected an error for a start without a due date")
+	}
+
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	instant := time.Date(2017, time.March, 5, 20, 0, 0, 0, time.UTC)
+	if got, want := asana.DateOf(instant.In(loc)), asana.NewDate(2017, time.March, 6); got != want {
+		t.Errorf("DateOf: got %s want %s", got, want)
+	}
+}
+
+func TestCreateTaskStartOnWithDueAt(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	start := asana.NewDate(2017, time.October, 2)
+	dueAt := time.Date(2017, time.October, 6, 17, 0, 0, 0, time.UTC)
+	earlyDueAt := time.Date(2017, time.September, 29, 17, 0, 0, 0, time.UTC)
+
+	tests := [...]struct {
+		treq    *asana.TaskRequest
+		wantErr bool
+	}{
+		0: {treq: &asana.TaskRequest{Name: "x", Workspace: "1", StartOn: &start, DueAt: &dueAt}},
+		1: {treq: &asana.TaskRequest{Name: "x", Workspace: "1", StartOn: &start, DueAt: &earlyDueAt}, wantErr: true},
+		2: {treq: &asana.TaskRequest{Name: "x", Workspace: "1", StartOn: &start}, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{
+			resps: []scriptedResp{{code: http.StatusCreated, body: `{"data": {"id": 1}}`}},
+		}
+		client.SetHTTPRoundTripper(be)
+
+		_, err := client.CreateTask(tt.treq)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+		}
+		if len(be.reqs) != 1 {
+			t.Errorf("#%d: got %d requests want 1", i, len(be.reqs))
+		}
+	}
+
+	task := &asana.Task{StartOn: &start, DueAt: &dueAt}
+	if got, want := task.Dates().Due, asana.NewDate(2017, time.October, 6); got != want {
+		t.Errorf("Dates: got due %s want %s", got, want)
+	}
+}
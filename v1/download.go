@@ -0,0 +1,169 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/orijtech/otils"
+)
+
+// AttachmentDownload describes the content of a downloaded attachment.
+type AttachmentDownload struct {
+	Attachment *Attachment
+
+	ContentType string
+
+	// ContentLength is as reported by the server, -1 if unknown.
+	ContentLength int64
+
+	// SHA256 is the hex encoded checksum of the content.
+	// It is only set by DownloadAttachmentToPath.
+	SHA256 string
+}
+
+var (
+	errNoDownloadURL = errors.New("attachment has no download URL")
+
+	errEmptyDownloadPath = errors.New("expecting a non-empty path to download to")
+)
+
+// maxDownloadAttempts bounds how many times we'll fetch a fresh
+// download URL. Those of Asana hosted files expire within minutes.
+const maxDownloadAttempts = 2
+
+func downloadURLExpired(code int) bool {
+	return code == http.StatusForbidden || code == http.StatusGone
+}
+
+// DownloadAttachment streams the content of an attachment. The download URL
+// is retrieved afresh and, should it expire before the download starts,
+// retrieved once more. The caller must close the returned body.
+func (c *Client) DownloadAttachment(ctx context.Context, attachmentID string) (io.ReadCloser, *AttachmentDownload, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxDownloadAttempts; attempt++ {
+		attachment, err := c.findAttachmentByID(ctx, attachmentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		downloadURL := strings.TrimSpace(string(attachment.DownloadURL))
+		if downloadURL == "" {
+			return nil, nil, errNoDownloadURL
+		}
+
+		req, err := http.NewRequest("GET", downloadURL, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		req = req.WithContext(ctx)
+
+		// Download URLs are pre-signed so our Authorization header must not
+		// be sent along; the client follows redirects to the storage host.
+		res, err := c.httpClient().Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		if otils.StatusOK(res.StatusCode) {
+			download := &AttachmentDownload{
+				Attachment:    attachment,
+				ContentType:   res.Header.Get("Content-Type"),
+				ContentLength: res.ContentLength,
+			}
+			return res.Body, download, nil
+		}
+
+		errMsg := res.Status
+		if res.Body != nil {
+			slurp, _ := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			if len(slurp) > 0 {
+				errMsg = string(slurp)
+			}
+		}
+		lastErr = &HTTPError{msg: errMsg, code: res.StatusCode}
+		if !downloadURLExpired(res.StatusCode) {
+			break
+		}
+	}
+	return nil, nil, lastErr
+}
+
+type checksumMismatchError struct {
+	got  string
+	want string
+}
+
+func (cme *checksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch: got sha256 %s want %s", cme.got, cme.want)
+}
+
+// DownloadAttachmentToPath saves the content of an attachment to path. The
+// content is written to a temporary file in the same directory that is only
+// renamed to path once its length matches what the server reported and,
+// if wantSHA256 is set, its hex encoded SHA-256 checksum matches it.
+func (c *Client) DownloadAttachmentToPath(ctx context.Context, attachmentID, path, wantSHA256 string) (*AttachmentDownload, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errEmptyDownloadPath
+	}
+
+	body, download, err := c.DownloadAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	tmpf, err := ioutil.TempFile(dir, ".asana-download-")
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := tmpf.Name()
+	defer os.Remove(tmpPath)
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmpf, hash), body)
+	if cerr := tmpf.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if download.ContentLength > 0 && n != download.ContentLength {
+		return nil, fmt.Errorf("short download: got %d bytes want %d", n, download.ContentLength)
+	}
+	download.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	if wantSHA256 != "" && !strings.EqualFold(wantSHA256, download.SHA256) {
+		return nil, &checksumMismatchError{got: download.SHA256, want: wantSHA256}
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, err
+	}
+	return download, nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type EventAction string
+
+const (
+	ActionChanged   EventAction = "changed"
+	ActionAdded     EventAction = "added"
+	ActionRemoved   EventAction = "removed"
+	ActionDeleted   EventAction = "deleted"
+	ActionUndeleted EventAction = "undeleted"
+)
+
+// EventResource is the compact form of whatever an event refers
+// to, be it a task, project, story, tag or any other resource.
+type EventResource struct {
+	ID              int64  `json:"id"`
+	Name            string `json:"name,omitempty"`
+	ResourceType    string `json:"resource_type,omitempty"`
+	ResourceSubtype string `json:"resource_subtype,omitempty"`
+}
+
+// EventChange describes which field of the resource changed.
+// The values are left raw because their shape depends on Field.
+type EventChange struct {
+	Field        string          `json:"field"`
+	Action       EventAction     `json:"action"`
+	NewValue     json.RawMessage `json:"new_value,omitempty"`
+	AddedValue   json.RawMessage `json:"added_value,omitempty"`
+	RemovedValue json.RawMessage `json:"removed_value,omitempty"`
+}
+
+type Event struct {
+	// User is who triggered the event, it is unset
+	// for changes made by Asana itself e.g. rules.
+	User *NamedAndIDdEntity `json:"user,omitempty"`
+
+	Resource *EventResource `json:"resource"`
+	Action   EventAction    `json:"action"`
+
+	// Parent is set for added and removed events e.g. the
+	// project that a task was added to or removed from.
+	Parent *EventResource `json:"parent,omitempty"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	Change *EventChange `json:"change,omitempty"`
+}
+
+type EventsPage struct {
+	Events []*Event `json:"data"`
+
+	// Resynced is set when the stream's sync token had expired and
+	// was reissued, in which case some events may have been missed.
+	Resynced bool `json:"-"`
+
+	Err error `json:"-"`
+}
+
+type eventsPager struct {
+	EventsPage
+
+	SyncToken string `json:"sync"`
+	HasMore   bool   `json:"has_more"`
+}
+
+type EventStreamRequest struct {
+	// ResourceID is the task, project or other
+	// resource whose events should be streamed.
+	ResourceID string
+
+	// SyncToken resumes from where a previous stream left off,
+	// see EventStream.SyncToken. If unset, only events that
+	// happen after the stream is created are delivered.
+	SyncToken string
+
+	// PollInterval is how long to wait before checking
+	// for new events once the backlog has been drained.
+	PollInterval time.Duration
+}
+
+const defaultEventPollInterval = 5 * time.Second
+
+// EventStream polls the events endpoint for a single resource
+// while keeping track of the sync token between polls.
+type EventStream struct {
+	client *Client
+
+	resourceID   string
+	pollInterval time.Duration
+
+	mu        sync.Mutex
+	syncToken string
+}
+
+var (
+	errNilEventStreamRequest = errors.New("expecting a non-nil eventStreamRequest")
+	errEmptyResourceID       = errors.New("expecting a non-empty resourceID")
+)
+
+func (c *Client) NewEventStream(esr *EventStreamRequest) (*EventStream, error) {
+	if esr == nil {
+		return nil, errNilEventStreamRequest
+	}
+	resourceID := strings.TrimSpace(esr.ResourceID)
+	if resourceID == "" {
+		return nil, errEmptyResourceID
+	}
+	pollInterval := esr.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultEventPollInterval
+	}
+	es := &EventStream{
+		client:       c,
+		resourceID:   resourceID,
+		pollInterval: pollInterval,
+		syncToken:    strings.TrimSpace(esr.SyncToken),
+	}
+	return es, nil
+}
+
+// SyncToken returns the latest sync token, which can be
+// persisted to resume the stream later on without missing events.
+func (es *EventStream) SyncToken() string {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.syncToken
+}
+
+func (es *EventStream) setSyncToken(token string) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.syncToken = token
+}
+
+type syncTokenErrorWrap struct {
+	SyncToken string `json:"sync"`
+}
+
+// poll makes a single request to the events endpoint.
+// fresh is set if the server issued a new sync token instead of
+// returning events, either because we had none or it had expired.
+func (es *EventStream) poll(ctx context.Context) (pager *eventsPager, fresh bool, err error) {
+	qs := make(url.Values)
+	qs.Set("resource", es.resourceID)
+	if token := es.SyncToken(); token != "" {
+		qs.Set("sync", token)
+	}
+	fullURL := fmt.Sprintf("%s/events?%s", baseURL, qs.Encode())
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req = req.WithContext(ctx)
+
+	slurp, _, err := es.client.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		he, ok := err.(*HTTPError)
+		if !ok || he.Code() != http.StatusPreconditionFailed {
+			return nil, false, err
+		}
+		// 412 Precondition Failed carries a
+		// new sync token in its body.
+		stw := new(syncTokenErrorWrap)
+		if jerr := json.Unmarshal([]byte(he.msg), stw); jerr != nil || stw.SyncToken == "" {
+			return nil, false, err
+		}
+		es.setSyncToken(stw.SyncToken)
+		return nil, true, nil
+	}
+
+	pager = new(eventsPager)
+	if err := json.Unmarshal(slurp, pager); err != nil {
+		return nil, false, err
+	}
+	if pager.SyncToken != "" {
+		es.setSyncToken(pager.SyncToken)
+	}
+	return pager, false, nil
+}
+
+// Next blocks until new events are available, the sync token has had
+// to be reissued or ctx is done. It can be called in a loop to iterate
+// over the events of the resource.
+func (es *EventStream) Next(ctx context.Context) (*EventsPage, error) {
+	for {
+		hadToken := es.SyncToken() != ""
+		pager, fresh, err := es.poll(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if fresh {
+			if hadToken {
+				return &EventsPage{Resynced: true}, nil
+			}
+			// This was the very first poll which
+			// only establishes the sync token.
+			continue
+		}
+
+		if len(pager.Events) > 0 {
+			page := pager.EventsPage
+			return &page, nil
+		}
+		if pager.HasMore {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(es.pollInterval):
+		}
+	}
+}
+
+// Stream delivers events on the returned channel until ctx is done, at
+// which point the channel is closed. Failed polls are delivered as pages
+// with Err set and retried after the poll interval.
+func (es *EventStream) Stream(ctx context.Context) <-chan *EventsPage {
+	pagesChan := make(chan *EventsPage)
+	go func() {
+		defer close(pagesChan)
+
+		for {
+			page, err := es.Next(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				page = &EventsPage{Err: err}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case pagesChan <- page:
+			}
+
+			if err == nil {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(es.pollInterval):
+			}
+		}
+	}()
+
+	return pagesChan
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestEventStreamNext(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			// The first poll only issues a sync token.
+			{code: http.StatusPreconditionFailed, body: `{"sync": "token-1"}`},
+			{code: http.StatusOK, body: `{"sync": "token-2", "data": []}`},
+			{code: http.StatusOK, body: `{"sync": "token-3", "has_more": false, "data": [
+			  {"action": "changed", "resource": {"id": 1, "resource_type": "task"},
+			   "change": {"field": "completed", "action": "changed", "new_value": true}}
+			]}`},
+			// Then the token expires and is reissued.
+			{code: http.StatusPreconditionFailed, body: `{"sync": "token-4"}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	es, err := client.NewEventStream(&asana.EventStreamRequest{
+		ResourceID:   "42",
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("creating the stream: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	page, err := es.Next(ctx)
+	if err != nil {
+		t.Fatalf("first Next: %v", err)
+	}
+	if len(page.Events) != 1 || page.Resynced {
+		t.Fatalf("first Next: got %#v want 1 event", page)
+	}
+	ev := page.Events[0]
+	if ev.Action != asana.ActionChanged || ev.Resource.ID != 1 || ev.Change.Field != "completed" {
+		t.Errorf("first Next: got event %#v", ev)
+	}
+	if got, want := es.SyncToken(), "token-3"; got != want {
+		t.Errorf("sync token: got %q want %q", got, want)
+	}
+
+	page, err = es.Next(ctx)
+	if err != nil {
+		t.Fatalf("second Next: %v", err)
+	}
+	if !page.Resynced {
+		t.Errorf("second Next: expected a resync")
+	}
+	if got, want := es.SyncToken(), "token-4"; got != want {
+		t.Errorf("sync token: got %q want %q", got, want)
+	}
+
+	// Every poll but the first must have sent the previous token.
+	wantTokens := []string{"", "token-1", "token-2", "token-3"}
+	for i, req := range be.reqs {
+		if got := req.URL.Query().Get("sync"); got != wantTokens[i] {
+			t.Errorf("request #%d: got sync=%q want %q", i, got, wantTokens[i])
+		}
+	}
+}
+
+func TestEventStreamCancel(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&cannedBackend{body: `{"sync": "token", "data": []}`})
+
+	es, err := client.NewEventStream(&asana.EventStreamRequest{
+		ResourceID:   "42",
+		SyncToken:    "token",
+		PollInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("creating the stream: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pagesChan := es.Stream(ctx)
+	cancel()
+
+	select {
+	case _, open := <-pagesChan:
+		if open {
+			t.Errorf("expected no pages after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the stream was not closed after cancellation")
+	}
+}
@@ -15,9 +15,14 @@
 package asana_test
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/orijtech/asana/v1"
 )
@@ -467,12 +472,971 @@ func Example_client_ListAllAttachmentsForTask() {
 		log.Fatal(err)
 	}
 
-	attachmentsPage, err := client.ListAllAttachmentsForTask("331727965981099")
+	pagesChan, _, err := client.ListAllAttachmentsForTask("331727965981099", &asana.AttachmentsListOptions{
+		FullRecords: true,
+		Hosts:       []string{"asana"},
+		MIMETypes:   []string{"image/*"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pageCount := 0
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page: #%d err: %v", pageCount, err)
+			continue
+		}
+
+		for i, attachment := range page.Attachments {
+			fmt.Printf("Page: #%d Attachment #%d: %#v\n\n", pageCount, i, attachment)
+		}
+		pageCount += 1
+	}
+}
+
+func Example_client_CreateCustomField() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	priority, err := client.CreateCustomField(&asana.CustomFieldRequest{
+		Workspace: "331783765164429",
+		Name:      "Priority",
+		Type:      asana.CustomFieldTypeEnum,
+		EnumOptions: []*asana.EnumOptionRequest{
+			{Name: "Low", Color: "green"},
+			{Name: "High", Color: "red"},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Created custom field: %#v", priority)
+
+	urgent, err := client.CreateEnumOption(&asana.EnumOptionRequest{
+		CustomFieldID: fmt.Sprintf("%d", priority.ID),
+		Name:          "Urgent",
+		Color:         "purple",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Added enum option: %#v", urgent)
+}
+
+func Example_client_UpdateTaskCustomFields() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	task, err := client.FindTaskByID("332508471165497")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	estimate := task.CustomFieldByName("Estimate")
+	if estimate == nil {
+		log.Fatal("task has no \"Estimate\" custom field")
+	}
+	if hours, ok := estimate.Number(); ok {
+		log.Printf("Current estimate: %.1f hours", hours)
+	}
+	if err := estimate.SetNumber(12.5); err != nil {
+		log.Fatal(err)
+	}
+
+	updated, err := client.UpdateTaskCustomFields("332508471165497", estimate)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Updated task: %#v", updated)
+}
+
+func Example_client_ListCustomFieldsForWorkspace() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, _, err := client.ListCustomFieldsForWorkspace("331783765164429")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pageCount := 0
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page: #%d err: %v", pageCount, err)
+			continue
+		}
+
+		for i, customField := range page.CustomFields {
+			log.Printf("Page: #%d i: %d customField: %#v", pageCount, i, customField)
+		}
+		pageCount += 1
+	}
+}
+
+func Example_client_AddCustomFieldSetting() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	proj, err := client.CreateProject(&asana.ProjectRequest{
+		Name:      "Customer onboarding",
+		Workspace: "331783765164429",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	projectID := fmt.Sprintf("%d", proj.ID)
+	// Priority goes first and shows up in list views, Estimate after it.
+	settings := []*asana.CustomFieldSettingRequest{
+		{ProjectID: projectID, CustomFieldID: "338179717217400", IsImportant: true},
+		{ProjectID: projectID, CustomFieldID: "338179717217401", InsertAfter: "338179717217400"},
+	}
+	for _, setting := range settings {
+		cfs, err := client.AddCustomFieldSetting(setting)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Added custom field setting: %#v", cfs)
+	}
+}
+
+func Example_client_ListCustomFieldSettingsForProject() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, _, err := client.ListCustomFieldSettingsForProject("332697649493087")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pageCount := 0
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page: #%d err: %v", pageCount, err)
+			continue
+		}
+
+		for i, setting := range page.CustomFieldSettings {
+			log.Printf("Page: #%d i: %d setting: %#v", pageCount, i, setting)
+		}
+		pageCount += 1
+	}
+}
+
+func Example_client_RemoveCustomFieldSetting() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = client.RemoveCustomFieldSetting(&asana.CustomFieldSettingRequest{
+		ProjectID:     "332697649493087",
+		CustomFieldID: "338179717217401",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Example_client_SearchTasks() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nextWeek := time.Now().AddDate(0, 0, 7)
+	search := asana.NewTaskSearch("331783765164429").
+		Text("release").
+		AssigneeAny("me").
+		ProjectsAny("332697649493087").
+		DueOnBefore(nextWeek).
+		Completed(false).
+		SortBy(asana.SortByDueDate).
+		SortAscending(true)
+
+	pagesChan, _, err := client.SearchTasks(search)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pageCount := 0
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page: #%d err: %v", pageCount, err)
+			continue
+		}
+
+		for i, task := range page.Tasks {
+			log.Printf("Page: #%d i: %d task: %#v", pageCount, i, task)
+		}
+		pageCount += 1
+	}
+}
+
+func Example_client_SyncTasksForProject() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Checkpoints are kept on disk so that every run of
+	// this job only retrieves the tasks changed since the last.
+	store := asana.NewFileCheckpointStore("./asana-checkpoints.json")
+	result, err := client.SyncTasksForProject(&asana.TaskSync{
+		ProjectID: "332697649493087",
+		Store:     store,
+		Overlap:   time.Minute,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("%d tasks changed since %v", len(result.Tasks), result.Since)
+	for i, task := range result.Tasks {
+		log.Printf("i: %d task: %#v", i, task)
+	}
+}
+
+func Example_client_NewEventStream() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	es, err := client.NewEventStream(&asana.EventStreamRequest{
+		ResourceID:   "332697649493087",
+		PollInterval: 10 * time.Second,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	for page := range es.Stream(ctx) {
+		if err := page.Err; err != nil {
+			log.Printf("err: %v", err)
+			continue
+		}
+		if page.Resynced {
+			log.Printf("the sync token expired, some events may have been missed")
+		}
+		for _, event := range page.Events {
+			log.Printf("%s %s %d", event.Action, event.Resource.ResourceType, event.Resource.ID)
+		}
+	}
+}
+
+func Example_client_CreateWebhook() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	receiver := asana.NewWebhookReceiver("")
+	receiver.OnHandshake = func(req *http.Request, secret string) error {
+		// The target registered below carries a token that
+		// a spoofed handshake wouldn't know.
+		if req.URL.Query().Get("token") != os.Getenv("ASANA_HOOK_TOKEN") {
+			return fmt.Errorf("unexpected handshake")
+		}
+		// Persist the secret so that deliveries can
+		// still be verified after a restart.
+		return ioutil.WriteFile("./asana-hook-secret", []byte(secret), 0600)
+	}
+	receiver.HandleFunc(&asana.WebhookFilter{
+		ResourceType: "task",
+		Action:       asana.ActionChanged,
+		Fields:       []string{"completed"},
+	}, func(ev *asana.Event) error {
+		log.Printf("task %d was completed or reopened", ev.Resource.ID)
+		return nil
+	})
+
+	http.Handle("/asana-hook", receiver)
+	go func() {
+		log.Fatal(http.ListenAndServe(":8080", nil))
+	}()
+
+	hook, err := client.CreateWebhook(&asana.WebhookRequest{
+		ResourceID: "332697649493087",
+		Target:     "https://hooks.example.com/asana-hook?token=" + os.Getenv("ASANA_HOOK_TOKEN"),
+		Filters: []*asana.WebhookFilter{
+			{ResourceType: "task", Action: asana.ActionChanged, Fields: []string{"completed"}},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Created webhook: %#v", hook)
+}
+
+func Example_client_ListWebhooks() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, _, err := client.ListWebhooks(&asana.WebhookQuery{
+		WorkspaceID: "331783765164429",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pageCount := 0
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page: #%d err: %v", pageCount, err)
+			continue
+		}
+
+		for i, hook := range page.Webhooks {
+			log.Printf("Page: #%d i: %d webhook: %#v", pageCount, i, hook)
+		}
+		pageCount += 1
+	}
+}
+
+func Example_client_DeleteWebhook() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := client.DeleteWebhook("338179717217499"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Example_client_DoBatch() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	taskIDs := []string{"332508471165497", "332508471165498", "332508471165499"}
+	batch := asana.NewBatch()
+	for _, taskID := range taskIDs {
+		batch.Put(fmt.Sprintf("/tasks/%s", taskID), map[string]interface{}{
+			"completed": true,
+		})
+	}
+
+	results, err := client.DoBatch(batch)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, result := range results {
+		task, err := result.Task()
+		if err != nil {
+			log.Printf("task %s: err: %v", taskIDs[i], err)
+			continue
+		}
+		log.Printf("completed task: %#v", task)
+	}
+}
+
+func ExampleRunBulk() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	taskIDs := []string{"332508471165497", "332508471165498", "332508471165499"}
+	var ops []asana.BulkOperation
+	for _, taskID := range taskIDs {
+		taskID := taskID
+		ops = append(ops, func(ctx context.Context) (interface{}, error) {
+			return nil, client.DeleteTask(taskID)
+		})
+	}
+
+	result, err := asana.RunBulk(context.Background(), &asana.BulkRequest{
+		Operations:  ops,
+		Concurrency: 4,
+		Pacing:      100 * time.Millisecond,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := result.Err(); err != nil {
+		log.Printf("bulk deletion: %v", err)
+		for _, item := range result.Failed() {
+			log.Printf("task %s: %v", taskIDs[item.Index], item.Err)
+		}
+	}
+}
+
+func Example_client_DownloadAttachment() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	body, download, err := client.DownloadAttachment(ctx, "338179717217493")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer body.Close()
+
+	f, err := os.Create(string(download.Attachment.Name))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, body)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Downloaded %d bytes of %q", n, download.ContentType)
+}
+
+func Example_client_DownloadAttachmentToPath() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	download, err := client.DownloadAttachmentToPath(context.Background(), "338179717217493", "./downloads/report.pdf", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Saved %q with sha256 %s", download.Attachment.Name, download.SHA256)
+}
+
+func Example_client_AttachExternalURL() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	attachment, err := client.AttachExternalURL(&asana.ExternalAttachment{
+		ParentID: "331727965981099",
+		URL:      "https://ci.example.com/builds/1234/artifacts/report.html",
+		Name:     "Build #1234 report",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Linked attachment: %#v\n", attachment)
+}
+
+func Example_client_DeleteAttachment() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := client.DeleteAttachment("338179717217493"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Example_client_MirrorProjectAttachments() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	manifest, err := client.MirrorProjectAttachments(context.Background(), &asana.MirrorRequest{
+		ProjectID: "331727965981099",
+		Dir:       "./archive/331727965981099",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Mirrored %d attachments\n", len(manifest.Attachments))
+	for _, failed := range manifest.Failed() {
+		log.Printf("Attachment %d of task %d: %s", failed.AttachmentID, failed.TaskID, failed.Error)
+	}
+}
+
+func Example_client_CommentOnTask() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// <@12345> mentions the user whose ID is 12345.
+	markdown := "Build **failed** on `master`, see [the logs](https://ci.example.com/builds/1234).\n" +
+		"- <@12345> please take a look\n" +
+		"- ~~flaky test~~ ruled out"
+
+	story, err := client.CommentOnTask(&asana.CommentRequest{
+		TaskID:   "331727965981099",
+		HTMLText: asana.MarkdownToHTML(markdown),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Commented: %s\n", story.Text)
+}
+
+func ExampleParseRichText() {
+	rtn, err := asana.ParseRichText(`<body>Ship <strong>v2</strong> with <a data-asana-gid="12345"/>:<ul><li>docs</li><li><em>tests</em></li></ul></body>`)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(rtn.Markdown())
+	// Output:
+	// Ship **v2** with <@12345>:
+	// - docs
+	// - *tests*
+}
+
+func Example_client_CreateTaskWithDates() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	today := asana.Today(time.Local)
+	treq := &asana.TaskRequest{
+		Name:      "Quarterly report",
+		Workspace: "331783765164429",
+	}
+	if err := treq.SetDates(asana.DateRange{Start: today, Due: today.AddDays(14)}); err != nil {
+		log.Fatal(err)
+	}
+
+	task, err := client.CreateTask(treq)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dates := task.Dates()
+	fmt.Printf("Due in %d days\n", dates.Due.DaysSince(today))
+}
+
+func Example_client_ListPortfolioItems() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, _, err := client.ListPortfolioItems("1204917420912870")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Fatal(err)
+		}
+		for _, item := range page.Items {
+			if item.IsPortfolio() {
+				fmt.Printf("Nested portfolio: %s\n", item.Name)
+			} else {
+				fmt.Printf("Project: %s\n", item.Name)
+			}
+		}
+	}
+}
+
+func Example_client_AddPortfolioItem() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	portfolio, err := client.CreatePortfolio(&asana.PortfolioRequest{
+		Name:      "Q3 initiatives",
+		Workspace: "331783765164429",
+		Color:     "light-green",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = client.AddPortfolioItem(&asana.PortfolioItemRequest{
+		PortfolioID: fmt.Sprintf("%d", portfolio.ID),
+		ItemID:      "331727965981099",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := client.AddPortfolioMembers(fmt.Sprintf("%d", portfolio.ID), "12345", "67890"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Example_client_UpdateGoalMetricValue() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = client.SetGoalMetric(&asana.GoalMetricRequest{
+		GoalID:             "1204917420912901",
+		Unit:               asana.MetricUnitCurrency,
+		CurrencyCode:       "USD",
+		InitialNumberValue: 0,
+		TargetNumberValue:  1e6,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	goal, err := client.UpdateGoalMetricValue("1204917420912901", 250e3)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%q is %.0f%% of the way there\n", goal.Name, 100*goal.Metric.Progress())
+}
+
+func Example_client_ListGoals() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, _, err := client.ListGoals(&asana.GoalQuery{
+		WorkspaceID:   "331783765164429",
+		TimePeriodIDs: []string{"1204917420912555"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Fatal(err)
+		}
+		for _, goal := range page.Goals {
+			fmt.Printf("%s: %s\n", goal.Name, goal.Status)
+		}
+	}
+}
+
+func Example_client_CreateStatusUpdate() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	update, err := client.CreateStatusUpdate(&asana.StatusUpdateRequest{
+		ParentID:   "331727965981099",
+		Title:      "Release 1.4: week 3",
+		StatusType: asana.StatusUpdateAtRisk,
+		HTMLText:   asana.MarkdownToHTML("**2 blockers** remain:\n- flaky upgrade tests\n- pending security review"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Published status update %d\n", update.ID)
+}
+
+func Example_client_DuplicateProject() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	startOn := asana.NewDate(2017, time.November, 6)
+	job, err := client.DuplicateProject(&asana.ProjectDuplicateRequest{
+		ProjectID: "331727965981099",
+		Name:      "Onboarding: Acme Inc",
+		Include: []asana.ProjectDuplicateOption{
+			asana.DuplicateMembers, asana.DuplicateNotes,
+			asana.DuplicateTaskNotes, asana.DuplicateTaskSubtasks,
+			asana.DuplicateTaskDates,
+		},
+		ScheduleDates: &asana.ScheduleDates{StartOn: &startOn, ShouldSkipWeekends: true},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	project, err := client.WaitForNewProject(ctx, job)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Created project %d: %q\n", project.ID, project.Name)
+}
+
+func Example_client_InstantiateProjectTemplate() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	template, err := client.FindProjectTemplateByID("1198764052212945")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pti := &asana.ProjectTemplateInstantiation{
+		ProjectTemplateID: "1198764052212945",
+		Name:              "Onboarding: Globex",
+		TeamID:            "14916",
+	}
+	for _, rd := range template.RequestedDates {
+		pti.RequestedDates = append(pti.RequestedDates, &asana.RequestedDate{
+			GID:   rd.GID,
+			Value: asana.Today(time.UTC),
+		})
+	}
+
+	job, err := client.InstantiateProjectTemplate(pti)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	project, err := client.WaitForNewProject(context.Background(), job)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Created project %d from template %q\n", project.ID, template.Name)
+}
+
+func Example_client_WaitForJob() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	result, err := client.WaitForJob(ctx, "1203412563489001", &asana.JobWaitOptions{
+		MaxInterval: 30 * time.Second,
+		Progress: func(job *asana.Job, attempt int) {
+			log.Printf("job %d: %s (poll #%d)", job.ID, job.Status, attempt)
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch {
+	case result.Project != nil:
+		fmt.Printf("New project: %d %q\n", result.Project.ID, result.Project.Name)
+	case result.Task != nil:
+		fmt.Printf("New task: %d %q\n", result.Task.ID, result.Task.Name)
+	}
+}
+
+func Example_client_DuplicateTask() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	week := asana.Today(time.Local)
+	task, err := client.DuplicateTask(ctx, &asana.TaskDuplicateRequest{
+		TaskID: "331783765164429",
+		Name:   fmt.Sprintf("On-call checklist: week of %s", week),
+		Include: []asana.TaskDuplicateOption{
+			asana.TaskDuplicateAssignee, asana.TaskDuplicateNotes,
+			asana.TaskDuplicateSubtasks, asana.TaskDuplicateProjects,
+			asana.TaskDuplicateTags,
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Created task %d: %q\n", task.ID, task.Name)
+}
+
+func Example_client_ListTasksInUserTaskList() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	taskList, err := client.FindUserTaskList(asana.MeAsUser, "14916")
+	if err != nil {
+		log.Fatal(err)
+	}
+	taskListID := fmt.Sprintf("%d", taskList.ID)
+
+	sectionsChan, _, err := client.ListUserTaskListSections(taskListID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var sections []*asana.Section
+	for page := range sectionsChan {
+		if err := page.Err; err != nil {
+			log.Fatal(err)
+		}
+		sections = append(sections, page.Sections...)
+	}
+	today := asana.SectionForAssigneeStatus(sections, asana.StatusToday)
+	if today == nil {
+		log.Fatal("the \"Today\" section was renamed or deleted")
+	}
+
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	pagesChan, _, err := client.ListTasksInUserTaskList(&asana.UserTaskListRequest{
+		UserTaskListID: taskListID,
+		CompletedSince: &lastWeek,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Fatal(err)
+		}
+		for _, task := range page.Tasks {
+			if task.AssigneeSection != nil && task.AssigneeSection.ID == today.ID {
+				fmt.Printf("Today: %s\n", task.Name)
+			}
+		}
+	}
+}
+
+func Example_client_CreateTimeTrackingEntry() {
+	client, err := asana.NewClient()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	for i, task := range attachmentsPage.Attachments {
-		fmt.Printf("Task #%d: %#v\n\n", i, task)
+	entry, err := client.CreateTimeTrackingEntry(&asana.TimeTrackingEntryRequest{
+		TaskID:          "331783765164429",
+		DurationMinutes: 90,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Logged %d minutes on %s\n", entry.DurationMinutes, entry.EnteredOn)
+}
+
+func Example_client_TimeReportForProject() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Bill for last month.
+	today := asana.Today(time.UTC)
+	thisMonth := asana.NewDate(today.Year, today.Month, 1)
+	lastMonth := asana.NewDate(today.Year, today.Month-1, 1)
+	report, err := client.TimeReportForProject(context.Background(), &asana.TimeReportRequest{
+		ProjectID: "331727965981099",
+		Range:     asana.DateRange{Start: lastMonth, Due: thisMonth.AddDays(-1)},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Total: %.2f hours\n", float64(report.TotalMinutes)/60)
+	for _, ut := range report.Users {
+		fmt.Printf("%s: %.2f hours\n", ut.User.Name, float64(ut.Minutes)/60)
+	}
+}
+
+func Example_client_TimeReportForProject_user() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The time a contractor logged for a customer this month.
+	today := asana.Today(time.UTC)
+	report, err := client.TimeReportForProject(context.Background(), &asana.TimeReportRequest{
+		ProjectIDs: []string{"331727965981099", "331727965981100"},
+		UserID:     "14641",
+		Range:      asana.DateRange{Start: asana.NewDate(today.Year, today.Month, 1), Due: today},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Total: %.2f hours\n", float64(report.TotalMinutes)/60)
+	for projectID, minutes := range report.ProjectMinutes {
+		fmt.Printf("Project %s: %.2f hours\n", projectID, float64(minutes)/60)
+	}
+}
+
+func Example_client_Typeahead() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	projects, err := client.Typeahead(&asana.TypeaheadRequest{
+		WorkspaceID:  "14916",
+		ResourceType: asana.TypeaheadProject,
+		Query:        "onboa",
+		Count:        1,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(projects) == 0 {
+		log.Fatal("no matching project")
+	}
+
+	task, err := client.CreateTask(&asana.TaskRequest{
+		Name:      "Kickoff call",
+		Workspace: "14916",
+		Projects:  asana.TypeaheadEntities(projects),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Created task %d in %q\n", task.ID, projects[0].Name)
+}
+
+func Example_client_ExportAuditLogEvents() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Resume from wherever the previous export left off.
+	const offsetFile = "audit-log.offset"
+	offset, err := ioutil.ReadFile(offsetFile)
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatal(err)
+	}
+
+	f, err := os.OpenFile("audit-log.ndjson", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	since := time.Now().Add(-24 * time.Hour)
+	export, err := client.ExportAuditLogEvents(context.Background(), &asana.AuditLogQuery{
+		WorkspaceID: "14916",
+		StartAt:     &since,
+		EventTypes:  []string{"user_login_failed", "user_login_succeeded"},
+		Offset:      string(offset),
+	}, f)
+	if export != nil && export.Offset != "" {
+		if err := ioutil.WriteFile(offsetFile, []byte(export.Offset), 0600); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
 	}
+	fmt.Printf("Exported %d events\n", export.Events)
 }
@@ -0,0 +1,640 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type GoalStatus string
+
+const (
+	GoalGreen    GoalStatus = "green"
+	GoalYellow   GoalStatus = "yellow"
+	GoalRed      GoalStatus = "red"
+	GoalMissed   GoalStatus = "missed"
+	GoalAchieved GoalStatus = "achieved"
+	GoalPartial  GoalStatus = "partial"
+	GoalDropped  GoalStatus = "dropped"
+)
+
+type Goal struct {
+	ID        int64  `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+	HTMLNotes string `json:"html_notes,omitempty"`
+
+	Owner     *NamedAndIDdEntity `json:"owner,omitempty"`
+	Workspace *NamedAndIDdEntity `json:"workspace,omitempty"`
+
+	// Team is unset for goals of the whole workspace.
+	Team             *NamedAndIDdEntity `json:"team,omitempty"`
+	IsWorkspaceLevel *bool              `json:"is_workspace_level,omitempty"`
+
+	TimePeriod *TimePeriod `json:"time_period,omitempty"`
+	StartOn    *Date       `json:"start_on,omitempty"`
+	DueOn      *Date       `json:"due_on,omitempty"`
+
+	// Status is unset until the goal's first status update.
+	Status GoalStatus `json:"status,omitempty"`
+
+	Metric *GoalMetric `json:"metric,omitempty"`
+
+	Followers []*NamedAndIDdEntity `json:"followers,omitempty"`
+}
+
+type GoalMetricUnit string
+
+const (
+	MetricUnitNone       GoalMetricUnit = "none"
+	MetricUnitCurrency   GoalMetricUnit = "currency"
+	MetricUnitPercentage GoalMetricUnit = "percentage"
+)
+
+// GoalMetric is how progress towards a goal is measured.
+type GoalMetric struct {
+	ID int64 `json:"id,omitempty"`
+
+	Unit         GoalMetricUnit `json:"unit,omitempty"`
+	CurrencyCode string         `json:"currency_code,omitempty"`
+	Precision    int            `json:"precision,omitempty"`
+
+	InitialNumberValue float64 `json:"initial_number_value"`
+	TargetNumberValue  float64 `json:"target_number_value"`
+	CurrentNumberValue float64 `json:"current_number_value"`
+
+	// CurrentDisplayValue is the current value formatted by Asana.
+	CurrentDisplayValue string `json:"current_display_value,omitempty"`
+
+	// ProgressSource is "manual" unless the current value is
+	// computed by Asana e.g. from the goal's subgoals.
+	ProgressSource string `json:"progress_source,omitempty"`
+}
+
+// Progress returns how far along the current value is from the initial
+// to the target value, as a fraction that is 1 once the target is met.
+// It is not clamped so can be negative or exceed 1.
+func (gm *GoalMetric) Progress() float64 {
+	if gm == nil {
+		return 0
+	}
+	span := gm.TargetNumberValue - gm.InitialNumberValue
+	if span == 0 {
+		if gm.CurrentNumberValue == gm.TargetNumberValue {
+			return 1
+		}
+		return 0
+	}
+	return (gm.CurrentNumberValue - gm.InitialNumberValue) / span
+}
+
+type GoalRequest struct {
+	// GoalID is only used for updates.
+	GoalID string `json:"-"`
+
+	Name      string `json:"name,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+	HTMLNotes string `json:"html_notes,omitempty"`
+
+	Workspace string `json:"workspace,omitempty"`
+
+	// Team must be set unless IsWorkspaceLevel is true. IsWorkspaceLevel
+	// is a pointer so that updates can move a goal back to a team.
+	Team             string `json:"team,omitempty"`
+	IsWorkspaceLevel *bool  `json:"is_workspace_level,omitempty"`
+
+	Owner      string `json:"owner,omitempty"`
+	TimePeriod string `json:"time_period,omitempty"`
+
+	StartOn *Date `json:"start_on,omitempty"`
+	DueOn   *Date `json:"due_on,omitempty"`
+
+	Status GoalStatus `json:"status,omitempty"`
+
+	// Followers are only set when creating a goal, use
+	// AddGoalFollowers and RemoveGoalFollowers afterwards.
+	Followers []string `json:"followers,omitempty"`
+}
+
+var (
+	errNilGoalRequest = errors.New("expecting a non-nil goalRequest")
+	errEmptyGoalID    = errors.New("expecting a non-empty goalID")
+	errEmptyGoalName  = errors.New("expecting a non-empty name")
+	errEmptyGoalTeam  = errors.New("expecting either a team or a workspace level goal")
+)
+
+func (greq *GoalRequest) Validate() error {
+	if greq == nil {
+		return errNilGoalRequest
+	}
+	if strings.TrimSpace(greq.Workspace) == "" {
+		return errEmptyWorkspace
+	}
+	if strings.TrimSpace(greq.Name) == "" {
+		return errEmptyGoalName
+	}
+	if strings.TrimSpace(greq.Team) == "" && (greq.IsWorkspaceLevel == nil || !*greq.IsWorkspaceLevel) {
+		return errEmptyGoalTeam
+	}
+	return nil
+}
+
+type goalWrap struct {
+	Goal *Goal `json:"data"`
+}
+
+func parseOutGoalFromData(blob []byte) (*Goal, error) {
+	gw := new(goalWrap)
+	if err := json.Unmarshal(blob, gw); err != nil {
+		return nil, err
+	}
+	return gw.Goal, nil
+}
+
+func (c *Client) CreateGoal(greq *GoalRequest) (*Goal, error) {
+	if err := greq.Validate(); err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", "/goals", greq)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+func (c *Client) FindGoalByID(goalID string) (*Goal, error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	fullURL := fmt.Sprintf("%s/goals/%s", baseURL, goalID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+// UpdateGoal changes the attributes of the goal identified by GoalID.
+// Its Workspace cannot be changed.
+func (c *Client) UpdateGoal(greq *GoalRequest) (*Goal, error) {
+	if greq == nil {
+		return nil, errNilGoalRequest
+	}
+	goalID := strings.TrimSpace(greq.GoalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	if greq.Workspace != "" {
+		return nil, errImmutableWorkspace
+	}
+	update := *greq
+	update.Followers = nil
+	path := fmt.Sprintf("/goals/%s", goalID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("PUT", path, &update)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+func (c *Client) DeleteGoal(goalID string) error {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return errEmptyGoalID
+	}
+	fullURL := fmt.Sprintf("%s/goals/%s", baseURL, goalID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+// GoalQuery filters the goals listed by ListGoals. At
+// least one of WorkspaceID, TeamID, PortfolioID or
+// ProjectID must be set.
+type GoalQuery struct {
+	WorkspaceID string
+	TeamID      string
+	PortfolioID string
+	ProjectID   string
+
+	// TimePeriodIDs keeps only the goals of any of these time periods.
+	TimePeriodIDs []string
+
+	// WorkspaceLevelOnly excludes the goals of teams.
+	WorkspaceLevelOnly bool
+}
+
+var (
+	errNilGoalQuery   = errors.New("expecting a non-nil goalQuery")
+	errEmptyGoalQuery = errors.New("expecting a workspace, team, portfolio or project to list goals of")
+)
+
+func (gq *GoalQuery) urlValues() (url.Values, error) {
+	if gq == nil {
+		return nil, errNilGoalQuery
+	}
+	qs := make(url.Values)
+	for key, value := range map[string]string{
+		"workspace": gq.WorkspaceID,
+		"team":      gq.TeamID,
+		"portfolio": gq.PortfolioID,
+		"project":   gq.ProjectID,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			qs.Set(key, value)
+		}
+	}
+	if len(qs) == 0 {
+		return nil, errEmptyGoalQuery
+	}
+	// Asana takes the time periods as a single comma separated list.
+	var timePeriodIDs []string
+	for _, timePeriodID := range gq.TimePeriodIDs {
+		if timePeriodID = strings.TrimSpace(timePeriodID); timePeriodID != "" {
+			timePeriodIDs = append(timePeriodIDs, timePeriodID)
+		}
+	}
+	if len(timePeriodIDs) > 0 {
+		qs.Set("time_periods", strings.Join(timePeriodIDs, ","))
+	}
+	if gq.WorkspaceLevelOnly {
+		qs.Set("is_workspace_level", "true")
+	}
+	return qs, nil
+}
+
+type GoalsPage struct {
+	Goals []*Goal `json:"data"`
+	Err   error
+}
+
+type goalsPager struct {
+	GoalsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListGoals(gq *GoalQuery) (pagesChan chan *GoalsPage, cancelChan chan<- bool, err error) {
+	qs, err := gq.urlValues()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *GoalsPage)
+
+	go c.paginate(fmt.Sprintf("/goals?%s", qs.Encode()), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(goalsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.GoalsPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
+
+type GoalMetricRequest struct {
+	GoalID string `json:"-"`
+
+	Unit         GoalMetricUnit `json:"unit"`
+	CurrencyCode string         `json:"currency_code,omitempty"`
+	Precision    int            `json:"precision"`
+
+	InitialNumberValue float64 `json:"initial_number_value"`
+	TargetNumberValue  float64 `json:"target_number_value"`
+	CurrentNumberValue float64 `json:"current_number_value"`
+}
+
+var (
+	errNilGoalMetricRequest = errors.New("expecting a non-nil goalMetricRequest")
+	errEmptyCurrencyCode    = errors.New("expecting a currency code for a currency metric")
+)
+
+func (gmr *GoalMetricRequest) Validate() error {
+	if gmr == nil {
+		return errNilGoalMetricRequest
+	}
+	if strings.TrimSpace(gmr.GoalID) == "" {
+		return errEmptyGoalID
+	}
+	if gmr.Unit == MetricUnitCurrency && strings.TrimSpace(gmr.CurrencyCode) == "" {
+		return errEmptyCurrencyCode
+	}
+	return nil
+}
+
+// SetGoalMetric creates or replaces the metric
+// that the progress of the goal is measured by.
+func (c *Client) SetGoalMetric(gmr *GoalMetricRequest) (*Goal, error) {
+	if err := gmr.Validate(); err != nil {
+		return nil, err
+	}
+	metric := *gmr
+	if metric.Unit == "" {
+		metric.Unit = MetricUnitNone
+	}
+	path := fmt.Sprintf("/goals/%s/setMetric", strings.TrimSpace(gmr.GoalID))
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, &metric)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+type goalMetricValueRequest struct {
+	CurrentNumberValue float64 `json:"current_number_value"`
+}
+
+// UpdateGoalMetricValue records the latest value of the goal's metric.
+func (c *Client) UpdateGoalMetricValue(goalID string, current float64) (*Goal, error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	path := fmt.Sprintf("/goals/%s/setMetricCurrentValue", goalID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, &goalMetricValueRequest{CurrentNumberValue: current})
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+type goalFollowersRequest struct {
+	Followers []string `json:"followers"`
+}
+
+var errNoFollowers = errors.New("expecting at least one follower")
+
+func (c *Client) changeGoalFollowers(action, goalID string, userIDs []string) (*Goal, error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	if len(userIDs) == 0 {
+		return nil, errNoFollowers
+	}
+	path := fmt.Sprintf("/goals/%s/%s", goalID, action)
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, &goalFollowersRequest{Followers: userIDs})
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+func (c *Client) AddGoalFollowers(goalID string, userIDs ...string) (*Goal, error) {
+	return c.changeGoalFollowers("addFollowers", goalID, userIDs)
+}
+
+func (c *Client) RemoveGoalFollowers(goalID string, userIDs ...string) (*Goal, error) {
+	return c.changeGoalFollowers("removeFollowers", goalID, userIDs)
+}
+
+type GoalRelationshipType string
+
+const (
+	RelationshipSubgoal        GoalRelationshipType = "subgoal"
+	RelationshipSupportingWork GoalRelationshipType = "supporting_work"
+)
+
+// GoalSupporter is a subgoal, project or portfolio that supports a goal.
+type GoalSupporter struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+}
+
+type GoalRelationship struct {
+	ID int64 `json:"id,omitempty"`
+
+	Type GoalRelationshipType `json:"resource_subtype,omitempty"`
+
+	SupportedGoal      *NamedAndIDdEntity `json:"supported_goal,omitempty"`
+	SupportingResource *GoalSupporter     `json:"supporting_resource,omitempty"`
+
+	// ContributionWeight is how much the supporting resource
+	// counts towards the progress of the supported goal.
+	ContributionWeight float64 `json:"contribution_weight"`
+}
+
+type GoalRelationshipRequest struct {
+	GoalID string `json:"-"`
+
+	// SupportingResourceID is the ID of a goal, project or portfolio.
+	SupportingResourceID string `json:"supporting_resource"`
+
+	ContributionWeight *float64 `json:"contribution_weight,omitempty"`
+
+	// Only one of InsertBefore and InsertAfter can be set, to
+	// the ID of a subgoal that already supports the goal.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+var (
+	errNilGoalRelationshipRequest = errors.New("expecting a non-nil goalRelationshipRequest")
+	errEmptySupportingResourceID  = errors.New("expecting a non-empty supportingResourceID")
+)
+
+func (grr *GoalRelationshipRequest) Validate() error {
+	if grr == nil {
+		return errNilGoalRelationshipRequest
+	}
+	if strings.TrimSpace(grr.GoalID) == "" {
+		return errEmptyGoalID
+	}
+	if strings.TrimSpace(grr.SupportingResourceID) == "" {
+		return errEmptySupportingResourceID
+	}
+	if grr.InsertBefore != "" && grr.InsertAfter != "" {
+		return errBothInsertBeforeAndAfter
+	}
+	return nil
+}
+
+type goalRelationshipWrap struct {
+	GoalRelationship *GoalRelationship `json:"data"`
+}
+
+// AddGoalSupporter makes a subgoal, project or portfolio support the goal.
+func (c *Client) AddGoalSupporter(grr *GoalRelationshipRequest) (*GoalRelationship, error) {
+	if err := grr.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/goals/%s/addSupportingRelationship", strings.TrimSpace(grr.GoalID))
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, grr)
+	if err != nil {
+		return nil, err
+	}
+	grw := new(goalRelationshipWrap)
+	if err := json.Unmarshal(slurp, grw); err != nil {
+		return nil, err
+	}
+	return grw.GoalRelationship, nil
+}
+
+// RemoveGoalSupporter undoes AddGoalSupporter.
+// Only GoalID and SupportingResourceID are used.
+func (c *Client) RemoveGoalSupporter(grr *GoalRelationshipRequest) error {
+	if err := grr.Validate(); err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/goals/%s/removeSupportingRelationship", strings.TrimSpace(grr.GoalID))
+	_, _, err := c.doJSONReqThenSlurpBody("POST", path, &GoalRelationshipRequest{SupportingResourceID: grr.SupportingResourceID})
+	return err
+}
+
+type GoalRelationshipsPage struct {
+	Relationships []*GoalRelationship `json:"data"`
+	Err           error
+}
+
+type goalRelationshipsPager struct {
+	GoalRelationshipsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListGoalSupporters lists what supports the goal. If relType
+// is set only relationships of that type are listed.
+func (c *Client) ListGoalSupporters(goalID string, relType GoalRelationshipType) (pagesChan chan *GoalRelationshipsPage, cancelChan chan<- bool, err error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, nil, errEmptyGoalID
+	}
+
+	qs := make(url.Values)
+	qs.Set("supported_goal", goalID)
+	if relType != "" {
+		qs.Set("resource_subtype", string(relType))
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *GoalRelationshipsPage)
+
+	go c.paginate(fmt.Sprintf("/goal_relationships?%s", qs.Encode()), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(goalRelationshipsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.GoalRelationshipsPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
+
+// TimePeriod is a fiscal year, half or quarter that goals are set for.
+type TimePeriod struct {
+	ID          int64  `json:"id,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+
+	// Period is one of "FY", "H1", "H2", "Q1", "Q2", "Q3" and "Q4".
+	Period string `json:"period,omitempty"`
+
+	StartOn *Date `json:"start_on,omitempty"`
+	EndOn   *Date `json:"end_on,omitempty"`
+
+	Parent *TimePeriod `json:"parent,omitempty"`
+}
+
+// Dates returns the span of the time period.
+func (tp *TimePeriod) Dates() DateRange {
+	return DateRange{Start: dateOrZero(tp.StartOn), Due: dateOrZero(tp.EndOn)}
+}
+
+var errEmptyTimePeriodID = errors.New("expecting a non-empty timePeriodID")
+
+type timePeriodWrap struct {
+	TimePeriod *TimePeriod `json:"data"`
+}
+
+func (c *Client) FindTimePeriodByID(timePeriodID string) (*TimePeriod, error) {
+	timePeriodID = strings.TrimSpace(timePeriodID)
+	if timePeriodID == "" {
+		return nil, errEmptyTimePeriodID
+	}
+	fullURL := fmt.Sprintf("%s/time_periods/%s", baseURL, timePeriodID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	tpw := new(timePeriodWrap)
+	if err := json.Unmarshal(slurp, tpw); err != nil {
+		return nil, err
+	}
+	return tpw.TimePeriod, nil
+}
+
+type TimePeriodsPage struct {
+	TimePeriods []*TimePeriod `json:"data"`
+	Err         error
+}
+
+type timePeriodsPager struct {
+	TimePeriodsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListTimePeriods lists the time periods of the workspace. Either
+// of startOn and endOn can be the zero Date to leave that end open.
+func (c *Client) ListTimePeriods(workspaceID string, startOn, endOn Date) (pagesChan chan *TimePeriodsPage, cancelChan chan<- bool, err error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+
+	qs := make(url.Values)
+	qs.Set("workspace", workspaceID)
+	if !startOn.IsZero() {
+		qs.Set("start_on", startOn.String())
+	}
+	if !endOn.IsZero() {
+		qs.Set("end_on", endOn.String())
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *TimePeriodsPage)
+
+	go c.paginate(fmt.Sprintf("/time_periods?%s", qs.Encode()), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(timePeriodsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.TimePeriodsPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
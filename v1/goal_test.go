@@ -0,0 +1,404 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const goalJSON = `{"data": {
+  "id": 21, "name": "Ship v2", "status": "green",
+  "metric": {"unit": "percentage", "initial_number_value": 0, "target_number_value": 1, "current_number_value": 0.25}
+}}`
+
+func TestCreateGoal(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	teamLevel, workspaceLevel := false, true
+	tests := [...]struct {
+		req      *asana.GoalRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.GoalRequest{Workspace: "8", Name: "Ship v2"}, wantErr: true},
+		2: {req: &asana.GoalRequest{Workspace: "8", Name: "Ship v2", IsWorkspaceLevel: &teamLevel}, wantErr: true},
+		3: {
+			req:      &asana.GoalRequest{Workspace: "8", Name: "Ship v2", IsWorkspaceLevel: &workspaceLevel},
+			wantBody: `{"data":{"name":"Ship v2","workspace":"8","is_workspace_level":true}}`,
+		},
+		4: {
+			req:      &asana.GoalRequest{Workspace: "8", Name: "Ship v2", Team: "4"},
+			wantBody: `{"data":{"name":"Ship v2","workspace":"8","team":"4"}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: goalJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		_, err := client.CreateGoal(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		blob, _ := ioutil.ReadAll(be.reqs[0].Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+	}
+}
+
+func TestUpdateGoal(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	teamLevel := false
+	tests := [...]struct {
+		req      *asana.GoalRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.GoalRequest{Name: "Ship v2"}, wantErr: true},
+		2: {req: &asana.GoalRequest{GoalID: "21", Workspace: "8"}, wantErr: true},
+		3: {
+			// Moving a goal back to a team must send the false value.
+			req:      &asana.GoalRequest{GoalID: " 21 ", Team: "4", IsWorkspaceLevel: &teamLevel},
+			wantBody: `{"data":{"team":"4","is_workspace_level":false}}`,
+		},
+		4: {
+			// Followers are managed separately and never sent on updates.
+			req:      &asana.GoalRequest{GoalID: "21", Name: "Ship v3", Followers: []string{"7"}},
+			wantBody: `{"data":{"name":"Ship v3"}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: goalJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		_, err := client.UpdateGoal(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "PUT /api/1.0/goals/21"; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+	}
+}
+
+func TestListGoalsQuery(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		query     *asana.GoalQuery
+		wantErr   bool
+		wantQuery url.Values
+	}{
+		0: {query: nil, wantErr: true},
+		1: {query: &asana.GoalQuery{TimePeriodIDs: []string{"1"}}, wantErr: true},
+		2: {
+			query:     &asana.GoalQuery{WorkspaceID: "8"},
+			wantQuery: url.Values{"workspace": {"8"}},
+		},
+		3: {
+			// Time periods are sent as one comma separated value.
+			query: &asana.GoalQuery{
+				TeamID:             " 4 ",
+				TimePeriodIDs:      []string{"101", " ", "102 "},
+				WorkspaceLevelOnly: true,
+			},
+			wantQuery: url.Values{
+				"team":               {"4"},
+				"time_periods":       {"101,102"},
+				"is_workspace_level": {"true"},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: `{"data": []}`}}}
+		client.SetHTTPRoundTripper(be)
+
+		pagesChan, _, err := client.ListGoals(tt.query)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		for page := range pagesChan {
+			if page.Err != nil {
+				t.Errorf("#%d: unexpected page error: %v", i, page.Err)
+			}
+		}
+		if g, w := be.reqs[0].URL.Query().Encode(), tt.wantQuery.Encode(); g != w {
+			t.Errorf("#%d: query:\ngot:  %s\nwant: %s", i, g, w)
+		}
+	}
+}
+
+func TestListGoalsPaging(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: `{"data": [{"id": 21}], "next_page": {"offset": "a", "path": "/goals?offset=a"}}`},
+			{code: http.StatusInternalServerError, body: `{"errors": [{"message": "boom"}]}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	pagesChan, _, err := client.ListGoals(&asana.GoalQuery{WorkspaceID: "8"})
+	if err != nil {
+		t.Fatalf("listing: %v", err)
+	}
+	var pages []*asana.GoalsPage
+	for page := range pagesChan {
+		pages = append(pages, page)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("got %d pages want 2", len(pages))
+	}
+	if pages[0].Err != nil || len(pages[0].Goals) != 1 || pages[0].Goals[0].ID != 21 {
+		t.Errorf("unexpected first page: %+v", pages[0])
+	}
+	// A page that failed to load ends the listing.
+	if pages[1].Err == nil {
+		t.Errorf("expected the second page to carry the error")
+	}
+	if g, w := be.reqs[1].URL.Query().Get("offset"), "a"; g != w {
+		t.Errorf("got offset %q want %q", g, w)
+	}
+}
+
+func TestGoalMetric(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		req      *asana.GoalMetricRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.GoalMetricRequest{TargetNumberValue: 1}, wantErr: true},
+		2: {req: &asana.GoalMetricRequest{GoalID: "21", Unit: asana.MetricUnitCurrency}, wantErr: true},
+		3: {
+			// The unit defaults to none.
+			req:      &asana.GoalMetricRequest{GoalID: "21", TargetNumberValue: 10},
+			wantBody: `{"data":{"unit":"none","precision":0,"initial_number_value":0,"target_number_value":10,"current_number_value":0}}`,
+		},
+		4: {
+			req: &asana.GoalMetricRequest{
+				GoalID: "21", Unit: asana.MetricUnitCurrency, CurrencyCode: "EUR",
+				Precision: 2, InitialNumberValue: 100, TargetNumberValue: 500,
+			},
+			wantBody: `{"data":{"unit":"currency","currency_code":"EUR","precision":2,"initial_number_value":100,"target_number_value":500,"current_number_value":0}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: goalJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		_, err := client.SetGoalMetric(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/goals/21/setMetric"; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+	}
+
+	if _, err := client.UpdateGoalMetricValue(" ", 1); err == nil {
+		t.Errorf("expected an error for an empty goalID")
+	}
+
+	be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: goalJSON}}}
+	client.SetHTTPRoundTripper(be)
+	goal, err := client.UpdateGoalMetricValue("21", 0.25)
+	if err != nil {
+		t.Fatalf("updating the metric value: %v", err)
+	}
+	req := be.reqs[0]
+	if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/goals/21/setMetricCurrentValue"; g != w {
+		t.Errorf("got %q want %q", g, w)
+	}
+	blob, _ := ioutil.ReadAll(req.Body)
+	if g, w := string(blob), `{"data":{"current_number_value":0.25}}`; g != w {
+		t.Errorf("body:\ngot:  %s\nwant: %s", g, w)
+	}
+	if g, w := goal.Metric.Progress(), 0.25; g != w {
+		t.Errorf("got progress %v want %v", g, w)
+	}
+}
+
+func TestGoalSupporters(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	weight := 0.5
+	tests := [...]struct {
+		req      *asana.GoalRelationshipRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.GoalRelationshipRequest{SupportingResourceID: "22"}, wantErr: true},
+		2: {req: &asana.GoalRelationshipRequest{GoalID: "21"}, wantErr: true},
+		3: {
+			req:     &asana.GoalRelationshipRequest{GoalID: "21", SupportingResourceID: "22", InsertBefore: "23", InsertAfter: "24"},
+			wantErr: true,
+		},
+		4: {
+			req:      &asana.GoalRelationshipRequest{GoalID: "21", SupportingResourceID: "22"},
+			wantBody: `{"data":{"supporting_resource":"22"}}`,
+		},
+		5: {
+			req:      &asana.GoalRelationshipRequest{GoalID: "21", SupportingResourceID: "22", ContributionWeight: &weight, InsertAfter: "24"},
+			wantBody: `{"data":{"supporting_resource":"22","contribution_weight":0.5,"insert_after":"24"}}`,
+		},
+	}
+
+	relationshipJSON := `{"data": {"id": 40, "resource_subtype": "subgoal",
+	  "supported_goal": {"id": 21}, "supporting_resource": {"id": 22, "resource_type": "goal"},
+	  "contribution_weight": 0.5}}`
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: relationshipJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		rel, err := client.AddGoalSupporter(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/goals/21/addSupportingRelationship"; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+		if rel.Type != asana.RelationshipSubgoal || rel.SupportingResource == nil || rel.SupportingResource.ID != 22 {
+			t.Errorf("#%d: unexpected relationship: %+v", i, rel)
+		}
+	}
+
+	// Removing a supporter only sends the supporting resource.
+	be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: `{"data": {}}`}}}
+	client.SetHTTPRoundTripper(be)
+	err = client.RemoveGoalSupporter(&asana.GoalRelationshipRequest{GoalID: "21", SupportingResourceID: "22", InsertAfter: "24"})
+	if err != nil {
+		t.Fatalf("removing the supporter: %v", err)
+	}
+	req := be.reqs[0]
+	if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/goals/21/removeSupportingRelationship"; g != w {
+		t.Errorf("got %q want %q", g, w)
+	}
+	blob, _ := ioutil.ReadAll(req.Body)
+	if g, w := string(blob), `{"data":{"supporting_resource":"22"}}`; g != w {
+		t.Errorf("body:\ngot:  %s\nwant: %s", g, w)
+	}
+
+	be = &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: `{"data": [{"id": 40, "resource_subtype": "subgoal",
+			  "supporting_resource": {"id": 22, "name": "Beta", "resource_type": "goal"}}]}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+	pagesChan, _, err := client.ListGoalSupporters("21", asana.RelationshipSubgoal)
+	if err != nil {
+		t.Fatalf("listing the supporters: %v", err)
+	}
+	var subgoals []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("unexpected page error: %v", page.Err)
+		}
+		for _, rel := range page.Relationships {
+			subgoals = append(subgoals, rel.SupportingResource.Name)
+		}
+	}
+	if len(subgoals) != 1 || subgoals[0] != "Beta" {
+		t.Errorf("got subgoals %q", subgoals)
+	}
+	qs := be.reqs[0].URL.Query()
+	if g, w := qs.Get("supported_goal")+" "+qs.Get("resource_subtype"), "21 subgoal"; g != w {
+		t.Errorf("got query %q want %q", g, w)
+	}
+}
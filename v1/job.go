@@ -0,0 +1,212 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobNotStarted JobStatus = "not_started"
+	JobInProgress JobStatus = "in_progress"
+	JobSucceeded  JobStatus = "succeeded"
+	JobFailed     JobStatus = "failed"
+)
+
+// Job tracks an operation that Asana runs asynchronously,
+// such as duplicating a project or instantiating a template.
+type Job struct {
+	ID int64 `json:"id,omitempty"`
+
+	// ResourceSubtype is the kind of operation e.g. "duplicate_project".
+	ResourceSubtype string    `json:"resource_subtype,omitempty"`
+	Status          JobStatus `json:"status,omitempty"`
+
+	NewProject *NamedAndIDdEntity `json:"new_project,omitempty"`
+	NewTask    *NamedAndIDdEntity `json:"new_task,omitempty"`
+}
+
+// Done reports whether the job has either succeeded or failed.
+func (j *Job) Done() bool {
+	return j != nil && (j.Status == JobSucceeded || j.Status == JobFailed)
+}
+
+var errEmptyJobID = errors.New("expecting a non-empty jobID")
+
+type jobWrap struct {
+	Job *Job `json:"data"`
+}
+
+func parseOutJobFromData(blob []byte) (*Job, error) {
+	jw := new(jobWrap)
+	if err := json.Unmarshal(blob, jw); err != nil {
+		return nil, err
+	}
+	return jw.Job, nil
+}
+
+func (c *Client) FindJobByID(jobID string) (*Job, error) {
+	return c.findJobByID(context.Background(), jobID)
+}
+
+func (c *Client) findJobByID(ctx context.Context, jobID string) (*Job, error) {
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return nil, errEmptyJobID
+	}
+	fullURL := fmt.Sprintf("%s/jobs/%s", baseURL, jobID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutJobFromData(slurp)
+}
+
+type jobFailedError struct {
+	job *Job
+}
+
+func (jfe *jobFailedError) Error() string {
+	return fmt.Sprintf("job %d (%s) failed", jfe.job.ID, jfe.job.ResourceSubtype)
+}
+
+const (
+	defaultJobInitialInterval = 500 * time.Millisecond
+	defaultJobMaxInterval     = 10 * time.Second
+)
+
+// JobWaitOptions configures how WaitForJob polls a job.
+// The zero value and nil both use the defaults.
+type JobWaitOptions struct {
+	// InitialInterval is the wait before the first poll, and is
+	// then doubled after every poll up until MaxInterval.
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+
+	// Progress if set is invoked with the job after every poll.
+	Progress func(job *Job, attempt int)
+}
+
+func (jwo *JobWaitOptions) intervals() (initial, max time.Duration) {
+	initial, max = defaultJobInitialInterval, defaultJobMaxInterval
+	if jwo != nil && jwo.InitialInterval > 0 {
+		initial = jwo.InitialInterval
+	}
+	if jwo != nil && jwo.MaxInterval > 0 {
+		max = jwo.MaxInterval
+	}
+	if initial > max {
+		initial = max
+	}
+	return initial, max
+}
+
+// JobResult is a completed job and the resource that it created,
+// whose type depends on the kind of job. At most one of Project
+// and Task is set.
+type JobResult struct {
+	Job *Job
+
+	Project *Project
+	Task    *Task
+}
+
+// WaitForJob polls the job with exponential backoff until it either
+// succeeds or fails, or until ctx is done. On success, the project
+// or task that the job created is retrieved and returned.
+func (c *Client) WaitForJob(ctx context.Context, jobID string, opts *JobWaitOptions) (*JobResult, error) {
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return nil, errEmptyJobID
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	interval, maxInterval := opts.intervals()
+	var job *Job
+	for attempt := 1; !job.Done(); attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+			if interval *= 2; interval > maxInterval {
+				interval = maxInterval
+			}
+		}
+
+		var err error
+		job, err = c.findJobByID(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if job == nil {
+			return nil, fmt.Errorf("job %q not found", jobID)
+		}
+		if opts != nil && opts.Progress != nil {
+			opts.Progress(job, attempt)
+		}
+	}
+	if job.Status == JobFailed {
+		return nil, &jobFailedError{job: job}
+	}
+
+	result := &JobResult{Job: job}
+	var err error
+	switch {
+	case job.NewProject != nil:
+		result.Project, err = c.findProjectByID(ctx, fmt.Sprintf("%d", job.NewProject.ID))
+	case job.NewTask != nil:
+		result.Task, err = c.findTaskByID(ctx, fmt.Sprintf("%d", job.NewTask.ID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var errNoNewProject = errors.New("the job didn't create a project")
+
+// WaitForNewProject waits for a job that creates a project, such as
+// those returned by DuplicateProject and InstantiateProjectTemplate,
+// to complete and then retrieves the project.
+func (c *Client) WaitForNewProject(ctx context.Context, job *Job) (*Project, error) {
+	if job == nil {
+		return nil, errEmptyJobID
+	}
+	result, err := c.WaitForJob(ctx, fmt.Sprintf("%d", job.ID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.Project == nil {
+		return nil, errNoNewProject
+	}
+	return result.Project, nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestWaitForJob(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		resps      []scriptedResp
+		wantErr    bool
+		wantPaths  []string
+		wantStates []asana.JobStatus
+		check      func(*asana.JobResult) bool
+	}{
+		0: {
+			resps: []scriptedResp{
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "not_started"}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "in_progress", "new_task": {"id": 12}}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "succeeded", "new_task": {"id": 12}}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 12, "name": "Weekly checklist"}}`},
+			},
+			wantPaths: []string{
+				"/api/1.0/jobs/9", "/api/1.0/jobs/9", "/api/1.0/jobs/9", "/api/1.0/tasks/12",
+			},
+			wantStates: []asana.JobStatus{asana.JobNotStarted, asana.JobInProgress, asana.JobSucceeded},
+			check: func(jr *asana.JobResult) bool {
+				return jr.Task != nil && jr.Task.Name == "Weekly checklist" && jr.Project == nil
+			},
+		},
+		1: {
+			resps: []scriptedResp{
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "succeeded", "new_project": {"id": 77}}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 77, "name": "Onboarding"}}`},
+			},
+			wantPaths:  []string{"/api/1.0/jobs/9", "/api/1.0/projects/77"},
+			wantStates: []asana.JobStatus{asana.JobSucceeded},
+			check: func(jr *asana.JobResult) bool {
+				return jr.Project != nil && jr.Project.ID == 77 && jr.Task == nil
+			},
+		},
+		2: {
+			resps: []scriptedResp{
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "in_progress"}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "failed"}}`},
+			},
+			wantErr:    true,
+			wantPaths:  []string{"/api/1.0/jobs/9", "/api/1.0/jobs/9"},
+			wantStates: []asana.JobStatus{asana.JobInProgress, asana.JobFailed},
+		},
+		3: {
+			resps: []scriptedResp{
+				{code: http.StatusNotFound, body: `{"errors": [{"message": "Unknown object: 9"}]}`},
+			},
+			wantErr:   true,
+			wantPaths: []string{"/api/1.0/jobs/9"},
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: tt.resps}
+		client.SetHTTPRoundTripper(be)
+
+		var states []asana.JobStatus
+		var attempts []int
+		opts := &asana.JobWaitOptions{
+			InitialInterval: time.Millisecond,
+			MaxInterval:     2 * time.Millisecond,
+			Progress: func(job *asana.Job, attempt int) {
+				states = append(states, job.Status)
+				attempts = append(attempts, attempt)
+			},
+		}
+		jr, err := client.WaitForJob(context.Background(), "9", opts)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+		} else if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+		} else if !tt.check(jr) {
+			t.Errorf("#%d: unexpected result: %+v", i, jr)
+		}
+
+		if g, w := len(be.reqs), len(tt.wantPaths); g != w {
+			t.Errorf("#%d: got %d requests want %d", i, g, w)
+			continue
+		}
+		for j, req := range be.reqs {
+			if g, w := req.URL.Path, tt.wantPaths[j]; g != w {
+				t.Errorf("#%d: request #%d: got path %q want %q", i, j, g, w)
+			}
+		}
+		if g, w := len(states), len(tt.wantStates); g != w {
+			t.Errorf("#%d: got %d progress reports want %d", i, g, w)
+			continue
+		}
+		for j, state := range states {
+			if state != tt.wantStates[j] || attempts[j] != j+1 {
+				t.Errorf("#%d: report #%d: got (%q, %d) want (%q, %d)", i, j, state, attempts[j], tt.wantStates[j], j+1)
+			}
+		}
+	}
+}
+
+func TestWaitForJobCanceled(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: `{"data": {"id": 9, "status": "in_progress"}}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := &asana.JobWaitOptions{
+		InitialInterval: time.Hour,
+		Progress:        func(*asana.Job, int) { cancel() },
+	}
+	if _, err := client.WaitForJob(ctx, "9", opts); err != context.Canceled {
+		t.Errorf("got err %v want %v", err, context.Canceled)
+	}
+	if g, w := len(be.reqs), 1; g != w {
+		t.Errorf("got %d requests want %d", g, w)
+	}
+}
+
+type jobTestContextKey struct{}
+
+func TestWaitForJobUsesContext(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		resps []scriptedResp
+	}{
+		0: {
+			resps: []scriptedResp{
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "in_progress"}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "succeeded", "new_task": {"id": 12}}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 12, "name": "Weekly checklist"}}`},
+			},
+		},
+		1: {
+			resps: []scriptedResp{
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "succeeded", "new_project": {"id": 77}}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 77, "name": "Onboarding"}}`},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: tt.resps}
+		client.SetHTTPRoundTripper(be)
+
+		ctx := context.WithValue(context.Background(), jobTestContextKey{}, i)
+		opts := &asana.JobWaitOptions{InitialInterval: time.Millisecond}
+		if _, err := client.WaitForJob(ctx, "9", opts); err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		if g, w := len(be.reqs), len(tt.resps); g != w {
+			t.Errorf("#%d: got %d requests want %d", i, g, w)
+		}
+		for j, req := range be.reqs {
+			if req.Context().Value(jobTestContextKey{}) != i {
+				t.Errorf("#%d: request #%d to %s was made without the context", i, j, req.URL.Path)
+			}
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MirrorManifestName is the name of the manifest
+// file written at the root of the mirror's directory.
+const MirrorManifestName = "manifest.json"
+
+type MirrorRequest struct {
+	ProjectID string
+
+	// Dir is where attachments are saved, as
+	// Dir/<taskID>/<attachmentID>-<name>.
+	Dir string
+}
+
+// MirroredAttachment is the manifest's entry for an attachment.
+type MirroredAttachment struct {
+	TaskID       int64  `json:"task_id"`
+	TaskName     string `json:"task_name,omitempty"`
+	AttachmentID int64  `json:"attachment_id"`
+	Name         string `json:"name"`
+
+	// Path is relative to the mirror's directory.
+	Path   string `json:"path,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+
+	DownloadedAt *time.Time `json:"downloaded_at,omitempty"`
+
+	// Error is set if the attachment couldn't be downloaded,
+	// in which case it is retried on the next run.
+	Error string `json:"error,omitempty"`
+}
+
+type MirrorManifest struct {
+	ProjectID   string                `json:"project_id"`
+	MirroredAt  time.Time             `json:"mirrored_at"`
+	Attachments []*MirroredAttachment `json:"attachments"`
+}
+
+// Failed returns the entries of the attachments that couldn't be downloaded.
+func (mm *MirrorManifest) Failed() []*MirroredAttachment {
+	var failed []*MirroredAttachment
+	for _, ma := range mm.Attachments {
+		if ma.Error != "" {
+			failed = append(failed, ma)
+		}
+	}
+	return failed
+}
+
+var (
+	errNilMirrorRequest = errors.New("expecting a non-nil mirrorRequest")
+	errEmptyMirrorDir   = errors.New("expecting a non-empty directory to mirror to")
+)
+
+func (mr *MirrorRequest) Validate() error {
+	if mr == nil {
+		return errNilMirrorRequest
+	}
+	if strings.TrimSpace(mr.ProjectID) == "" {
+		return errEmptyProjectID
+	}
+	if strings.TrimSpace(mr.Dir) == "" {
+		return errEmptyMirrorDir
+	}
+	return nil
+}
+
+// MirrorProjectAttachments downloads every Asana hosted attachment of the
+// project's tasks and their subtasks, recording them in a manifest saved
+// in the directory. Attachments already recorded by the manifest of a
+// previous run, whose file is still on disk with the same size, aren't
+// downloaded again. The manifest is saved after every task and also when
+// the mirror is interrupted, so rerunning it resumes where it left off.
+// Failures of individual attachments are recorded in the manifest, see
+// MirrorManifest.Failed, while the returned error is for failures to walk
+// the project or to save the manifest, in which case the manifest of what
+// was mirrored so far is returned along with it.
+func (c *Client) MirrorProjectAttachments(ctx context.Context, mr *MirrorRequest) (*MirrorManifest, error) {
+	if err := mr.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(mr.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	manifestPath := filepath.Join(mr.Dir, MirrorManifestName)
+	previous, err := readMirrorManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &mirror{
+		client:   c,
+		dir:      mr.Dir,
+		path:     manifestPath,
+		previous: previous,
+		manifest: &MirrorManifest{ProjectID: mr.ProjectID},
+		seen:     make(map[int64]bool),
+	}
+	if err := m.walkProject(ctx, mr.ProjectID); err != nil {
+		// Save what was done so far for the next run to resume from.
+		if serr := m.save(true); serr != nil {
+			return m.manifest, fmt.Errorf("%v; saving the manifest: %v", err, serr)
+		}
+		return m.manifest, err
+	}
+	if err := m.save(false); err != nil {
+		return nil, err
+	}
+	return m.manifest, nil
+}
+
+// mirror is the state of a single run of MirrorProjectAttachments.
+type mirror struct {
+	client *Client
+	dir    string
+	path   string
+
+	previous map[int64]*MirroredAttachment
+	manifest *MirrorManifest
+
+	// seen are the IDs of the attachments visited by this run.
+	seen map[int64]bool
+}
+
+func (m *mirror) walkProject(ctx context.Context, projectID string) error {
+	return m.client.walkProjectTasks(ctx, projectID, func(task *Task) error {
+		if err := m.mirrorTaskAttachments(ctx, task); err != nil {
+			return err
+		}
+		return m.save(true)
+	})
+}
+
+// save writes the manifest. A partial manifest also keeps the entries
+// of the previous run that haven't been visited yet by this one.
+func (m *mirror) save(partial bool) error {
+	m.manifest.MirroredAt = time.Now().UTC()
+	toSave := m.manifest
+	if partial {
+		copied := *m.manifest
+		copied.Attachments = append([]*MirroredAttachment(nil), m.manifest.Attachments...)
+		for _, ma := range m.previous {
+			if !m.seen[ma.AttachmentID] {
+				copied.Attachments = append(copied.Attachments, ma)
+			}
+		}
+		toSave = &copied
+	}
+	return writeMirrorManifest(m.path, toSave)
+}
+
+func (m *mirror) mirrorTaskAttachments(ctx context.Context, task *Task) error {
+	taskID := fmt.Sprintf("%d", task.ID)
+	pagesChan, cancel, err := m.client.ListAllAttachmentsForTask(taskID, &AttachmentsListOptions{
+		FullRecords: true,
+		Hosts:       []string{string(AttachmentAsana)},
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { cancel <- true }()
+
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			return err
+		}
+		for _, attachment := range page.Attachments {
+			if prev := m.previous[attachment.ID]; prev.stillOnDisk(m.dir) {
+				m.add(prev)
+				continue
+			}
+
+			ma := &MirroredAttachment{
+				TaskID:       task.ID,
+				TaskName:     task.Name,
+				AttachmentID: attachment.ID,
+				Name:         string(attachment.Name),
+				Path:         filepath.Join(taskID, mirrorFilename(attachment)),
+			}
+			attachmentID := fmt.Sprintf("%d", attachment.ID)
+			download, err := m.client.DownloadAttachmentToPath(ctx, attachmentID, filepath.Join(m.dir, ma.Path), "")
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				ma.Error = err.Error()
+			} else {
+				now := time.Now().UTC()
+				ma.DownloadedAt = &now
+				ma.SHA256 = download.SHA256
+				if fi, err := os.Stat(filepath.Join(m.dir, ma.Path)); err == nil {
+					ma.Size = fi.Size()
+				}
+			}
+			m.add(ma)
+		}
+	}
+	return nil
+}
+
+func (m *mirror) add(ma *MirroredAttachment) {
+	m.seen[ma.AttachmentID] = true
+	m.manifest.Attachments = append(m.manifest.Attachments, ma)
+}
+
+// stillOnDisk reports whether the entry was downloaded in
+// a previous run and its file hasn't since been tampered with.
+func (ma *MirroredAttachment) stillOnDisk(dir string) bool {
+	if ma == nil || ma.Error != "" || ma.Path == "" {
+		return false
+	}
+	fi, err := os.Stat(filepath.Join(dir, ma.Path))
+	return err == nil && fi.Mode().IsRegular() && fi.Size() == ma.Size
+}
+
+// mirrorFilename prefixes the attachment's name with its ID so that
+// attachments with the same name on a task don't clobber each other.
+func mirrorFilename(a *Attachment) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r == '/', r == '\\', r == ':', r < ' ':
+			return '_'
+		default:
+			return r
+		}
+	}, strings.TrimSpace(string(a.Name)))
+	if name == "" || name == "." || name == ".." {
+		return fmt.Sprintf("%d", a.ID)
+	}
+	return fmt.Sprintf("%d-%s", a.ID, name)
+}
+
+func readMirrorManifest(path string) (map[int64]*MirroredAttachment, error) {
+	byID := make(map[int64]*MirroredAttachment)
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return byID, nil
+		}
+		return nil, err
+	}
+	manifest := new(MirrorManifest)
+	if err := json.Unmarshal(blob, manifest); err != nil {
+		return nil, err
+	}
+	for _, ma := range manifest.Attachments {
+		byID[ma.AttachmentID] = ma
+	}
+	return byID, nil
+}
+
+func writeMirrorManifest(path string, manifest *MirrorManifest) error {
+	blob, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(path, blob)
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+// mirrorBackend serves a project with tasks 10 and 11, where task 10
+// has subtask 20, and each of them has a single attachment 100, 110
+// and 200 respectively.
+type mirrorBackend struct {
+	sync.Mutex
+	downloads []string
+
+	// interruptAt if set is the attachment whose
+	// download cancels the mirror's context.
+	interruptAt string
+	interrupt   func()
+}
+
+var _ http.RoundTripper = (*mirrorBackend)(nil)
+
+func (mb *mirrorBackend) RoundTrip(req *http.Request) (*http.Response, error) {
+	mb.Lock()
+	defer mb.Unlock()
+
+	path := req.URL.Path
+	if req.URL.Host == "files.example.com" {
+		id := strings.TrimPrefix(path, "/")
+		if id == mb.interruptAt {
+			mb.interrupt()
+			return nil, context.Canceled
+		}
+		mb.downloads = append(mb.downloads, id)
+		body := ioutil.NopCloser(strings.NewReader("content of " + id))
+		return makeResp("200 OK", http.StatusOK, body), nil
+	}
+
+	var blob string
+	switch path = strings.TrimPrefix(path, "/api/1.0"); path {
+	case "/projects/1/tasks":
+		blob = `{"data": [{"id": 10, "name": "Design"}, {"id": 11, "name": "Build"}]}`
+	case "/tasks/10/subtasks":
+		blob = `{"data": [{"id": 20, "name": "Mockups"}]}`
+	case "/tasks/11/subtasks", "/tasks/20/subtasks":
+		blob = `{"data": []}`
+	default:
+		var taskID, attachmentID int
+		if _, err := fmt.Sscanf(path, "/tasks/%d/attachments", &taskID); err == nil {
+			blob = fmt.Sprintf(`{"data": [{"id": %d0, "name": "file-%d.txt", "host": "asana"}]}`, taskID, taskID)
+		} else if _, err := fmt.Sscanf(path, "/attachments/%d", &attachmentID); err == nil {
+			blob = fmt.Sprintf(`{"data": {"id": %d, "name": "file.txt", "host": "asana",
+			  "download_url": "https://files.example.com/%d"}}`, attachmentID, attachmentID)
+		} else {
+			return makeResp("404 Not Found", http.StatusNotFound, nil), nil
+		}
+	}
+	return makeResp("200 OK", http.StatusOK, ioutil.NopCloser(strings.NewReader(blob))), nil
+}
+
+func mirroredIDs(manifest *asana.MirrorManifest) string {
+	var ids []string
+	for _, ma := range manifest.Attachments {
+		ids = append(ids, fmt.Sprintf("%d", ma.AttachmentID))
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestMirrorProjectAttachmentsResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "asana-mirror")
+	if err != nil {
+		t.Fatalf("creating the directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	// The first run is interrupted while downloading
+	// the attachment of the project's second task.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	be := &mirrorBackend{interruptAt: "110", interrupt: cancel}
+	client.SetHTTPRoundTripper(be)
+
+	mr := &asana.MirrorRequest{ProjectID: "1", Dir: dir}
+	manifest, err := client.MirrorProjectAttachments(ctx, mr)
+	if err != context.Canceled {
+		t.Fatalf("got err %v want %v", err, context.Canceled)
+	}
+	// The subtask's attachment was mirrored too.
+	if g, w := strings.Join(be.downloads, ","), "100,200"; g != w {
+		t.Errorf("first run: got downloads %q want %q", g, w)
+	}
+	if manifest == nil || mirroredIDs(manifest) != "100,200" {
+		t.Errorf("first run: unexpected manifest %+v", manifest)
+	}
+
+	// What was mirrored was saved despite the interruption.
+	blob, err := ioutil.ReadFile(filepath.Join(dir, asana.MirrorManifestName))
+	if err != nil {
+		t.Fatalf("reading the manifest: %v", err)
+	}
+	if !strings.Contains(string(blob), `"attachment_id": 200`) {
+		t.Errorf("the saved manifest is missing the subtask's attachment:\n%s", blob)
+	}
+
+	// The rerun only downloads what's left.
+	be = &mirrorBackend{}
+	client.SetHTTPRoundTripper(be)
+	manifest, err = client.MirrorProjectAttachments(context.Background(), mr)
+	if err != nil {
+		t.Fatalf("second run: unexpected error: %v", err)
+	}
+	if g, w := strings.Join(be.downloads, ","), "110"; g != w {
+		t.Errorf("second run: got downloads %q want %q", g, w)
+	}
+	if g, w := mirroredIDs(manifest), "100,110,200"; g != w {
+		t.Errorf("second run: got attachments %q want %q", g, w)
+	}
+	if failed := manifest.Failed(); len(failed) != 0 {
+		t.Errorf("second run: unexpected failures: %+v", failed)
+	}
+
+	for _, ma := range manifest.Attachments {
+		content, err := ioutil.ReadFile(filepath.Join(dir, ma.Path))
+		if err != nil {
+			t.Errorf("attachment %d: %v", ma.AttachmentID, err)
+			continue
+		}
+		if g, w := string(content), fmt.Sprintf("content of %d", ma.AttachmentID); g != w {
+			t.Errorf("attachment %d: got content %q want %q", ma.AttachmentID, g, w)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// pageDecoder turns the body of a page, or the error that fetching
+// it failed with, into the page to send and the token of the next
+// page. A nil page is not sent, e.g. when filters emptied it.
+type pageDecoder func(slurp []byte, err error) (page interface{}, next *pageToken)
+
+// paginate GETs path and then each next page, sending every decoded
+// page on pagesChan, a channel of the listing's page type, until the
+// last page, a page that failed to fetch, or cancel firing. It closes
+// pagesChan once done.
+func (c *Client) paginate(path string, pagesChan interface{}, cancel <-chan bool, decode pageDecoder) {
+	out := reflect.ValueOf(pagesChan)
+	defer out.Close()
+
+	for {
+		fullURL := fmt.Sprintf("%s%s", baseURL, path)
+		req, _ := http.NewRequest("GET", fullURL, nil)
+		slurp, _, err := c.doAuthReqThenSlurpBody(req)
+
+		page, np := decode(slurp, err)
+		if page != nil {
+			cases := []reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(cancel)},
+				{Dir: reflect.SelectSend, Chan: out, Send: reflect.ValueOf(page)},
+			}
+			if chosen, _, _ := reflect.Select(cases); chosen == 0 {
+				return
+			}
+		}
+
+		if err != nil || np == nil || np.Path == "" {
+			// End of this pagination
+			return
+		}
+		path = np.Path
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Portfolio struct {
+	ID    int64  `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+	CreatedBy *NamedAndIDdEntity `json:"created_by,omitempty"`
+	Owner     *NamedAndIDdEntity `json:"owner,omitempty"`
+	Workspace *NamedAndIDdEntity `json:"workspace,omitempty"`
+
+	Members []*NamedAndIDdEntity `json:"members,omitempty"`
+
+	StartOn *Date `json:"start_on,omitempty"`
+	DueOn   *Date `json:"due_on,omitempty"`
+
+	Public       bool   `json:"public,omitempty"`
+	PermalinkURL string `json:"permalink_url,omitempty"`
+
+	CustomFieldSettings []*CustomFieldSetting `json:"custom_field_settings,omitempty"`
+}
+
+type PortfolioRequest struct {
+	// PortfolioID is only used for updates.
+	PortfolioID string `json:"-"`
+
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+
+	Workspace string `json:"workspace,omitempty"`
+
+	// Members are the IDs of the users to share
+	// the portfolio with when creating it.
+	Members []string `json:"members,omitempty"`
+
+	StartOn *Date `json:"start_on,omitempty"`
+	DueOn   *Date `json:"due_on,omitempty"`
+
+	// Public is a pointer so that updates
+	// can make a portfolio private again.
+	Public *bool `json:"public,omitempty"`
+}
+
+var (
+	errNilPortfolioRequest = errors.New("expecting a non-nil portfolioRequest")
+	errEmptyPortfolioName  = errors.New("expecting a non-empty name")
+)
+
+func (preq *PortfolioRequest) Validate() error {
+	if preq == nil {
+		return errNilPortfolioRequest
+	}
+	if strings.TrimSpace(preq.Workspace) == "" {
+		return errEmptyWorkspace
+	}
+	if strings.TrimSpace(preq.Name) == "" {
+		return errEmptyPortfolioName
+	}
+	return nil
+}
+
+type portfolioWrap struct {
+	Portfolio *Portfolio `json:"data"`
+}
+
+func parseOutPortfolioFromData(blob []byte) (*Portfolio, error) {
+	pw := new(portfolioWrap)
+	if err := json.Unmarshal(blob, pw); err != nil {
+		return nil, err
+	}
+	return pw.Portfolio, nil
+}
+
+func (c *Client) CreatePortfolio(preq *PortfolioRequest) (*Portfolio, error) {
+	if err := preq.Validate(); err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", "/portfolios", preq)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutPortfolioFromData(slurp)
+}
+
+func (c *Client) FindPortfolioByID(portfolioID string) (*Portfolio, error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, errEmptyPortfolioID
+	}
+	fullURL := fmt.Sprintf("%s/portfolios/%s", baseURL, portfolioID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutPortfolioFromData(slurp)
+}
+
+// UpdatePortfolio changes the attributes of the portfolio identified by
+// PortfolioID. Its Workspace cannot be changed and Members are managed
+// with AddPortfolioMembers and RemovePortfolioMembers instead.
+func (c *Client) UpdatePortfolio(preq *PortfolioRequest) (*Portfolio, error) {
+	if preq == nil {
+		return nil, errNilPortfolioRequest
+	}
+	portfolioID := strings.TrimSpace(preq.PortfolioID)
+	if portfolioID == "" {
+		return nil, errEmptyPortfolioID
+	}
+	if preq.Workspace != "" {
+		return nil, errImmutableWorkspace
+	}
+	update := *preq
+	update.Members = nil
+	path := fmt.Sprintf("/portfolios/%s", portfolioID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("PUT", path, &update)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutPortfolioFromData(slurp)
+}
+
+func (c *Client) DeletePortfolio(portfolioID string) error {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return errEmptyPortfolioID
+	}
+	fullURL := fmt.Sprintf("%s/portfolios/%s", baseURL, portfolioID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type PortfoliosPage struct {
+	Portfolios []*Portfolio `json:"data"`
+	Err        error
+}
+
+type portfoliosPager struct {
+	PortfoliosPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListPortfolios lists the portfolios in the workspace that are owned by
+// ownerID. Asana only lists the portfolios of the authenticated user so
+// ownerID should be "me" or that user's ID.
+func (c *Client) ListPortfolios(workspaceID, ownerID string) (pagesChan chan *PortfoliosPage, cancelChan chan<- bool, err error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+	ownerID = strings.TrimSpace(ownerID)
+	if ownerID == "" {
+		return nil, nil, errEmptyUserID
+	}
+
+	qs := make(url.Values)
+	qs.Set("workspace", workspaceID)
+	qs.Set("owner", ownerID)
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *PortfoliosPage)
+
+	go c.paginate(fmt.Sprintf("/portfolios?%s", qs.Encode()), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(portfoliosPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.PortfoliosPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
+
+// PortfolioItem is an entry of a portfolio,
+// either a project or a nested portfolio.
+type PortfolioItem struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+}
+
+func (pi *PortfolioItem) IsPortfolio() bool {
+	return pi != nil && pi.ResourceType == "portfolio"
+}
+
+func (pi *PortfolioItem) IsProject() bool {
+	return pi != nil && pi.ResourceType == "project"
+}
+
+type PortfolioItemRequest struct {
+	PortfolioID string `json:"-"`
+
+	// ItemID is the ID of a project or of another portfolio.
+	ItemID string `json:"item"`
+
+	// Only one of InsertBefore and InsertAfter can be set, to the ID of
+	// an item already in the portfolio. Items are otherwise appended.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+var (
+	errNilPortfolioItemRequest = errors.New("expecting a non-nil portfolioItemRequest")
+	errEmptyItemID             = errors.New("expecting a non-empty itemID")
+)
+
+func (pir *PortfolioItemRequest) Validate() error {
+	if pir == nil {
+		return errNilPortfolioItemRequest
+	}
+	if strings.TrimSpace(pir.PortfolioID) == "" {
+		return errEmptyPortfolioID
+	}
+	if strings.TrimSpace(pir.ItemID) == "" {
+		return errEmptyItemID
+	}
+	if pir.InsertBefore != "" && pir.InsertAfter != "" {
+		return errBothInsertBeforeAndAfter
+	}
+	return nil
+}
+
+// AddPortfolioItem adds a project, or a portfolio to nest, to a portfolio.
+func (c *Client) AddPortfolioItem(pir *PortfolioItemRequest) error {
+	if err := pir.Validate(); err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/portfolios/%s/addItem", strings.TrimSpace(pir.PortfolioID))
+	_, _, err := c.doJSONReqThenSlurpBody("POST", path, pir)
+	return err
+}
+
+// RemovePortfolioItem removes an item from a portfolio.
+// Only PortfolioID and ItemID are used.
+func (c *Client) RemovePortfolioItem(pir *PortfolioItemRequest) error {
+	if err := pir.Validate(); err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/portfolios/%s/removeItem", strings.TrimSpace(pir.PortfolioID))
+	_, _, err := c.doJSONReqThenSlurpBody("POST", path, &PortfolioItemRequest{ItemID: pir.ItemID})
+	return err
+}
+
+type PortfolioItemsPage struct {
+	Items []*PortfolioItem `json:"data"`
+	Err   error
+}
+
+type portfolioItemsPager struct {
+	PortfolioItemsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListPortfolioItems lists the projects and nested portfolios of a
+// portfolio. Items of nested portfolios aren't listed; use
+// PortfolioItem.IsPortfolio to find the ones to descend into.
+func (c *Client) ListPortfolioItems(portfolioID string) (pagesChan chan *PortfolioItemsPage, cancelChan chan<- bool, err error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, nil, errEmptyPortfolioID
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *PortfolioItemsPage)
+
+	go c.paginate(fmt.Sprintf("/portfolios/%s/items?opt_fields=name,resource_type", portfolioID), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(portfolioItemsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.PortfolioItemsPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
+
+type PortfolioMembership struct {
+	ID        int64              `json:"id,omitempty"`
+	User      *NamedAndIDdEntity `json:"user,omitempty"`
+	Portfolio *NamedAndIDdEntity `json:"portfolio,omitempty"`
+}
+
+type portfolioMembersRequest struct {
+	// Members is a comma separated list of user IDs.
+	Members string `json:"members"`
+}
+
+var errNoMembers = errors.New("expecting at least one member")
+
+func (c *Client) changePortfolioMembers(action, portfolioID string, userIDs []string) error {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return errEmptyPortfolioID
+	}
+	if len(userIDs) == 0 {
+		return errNoMembers
+	}
+	for _, userID := range userIDs {
+		if strings.TrimSpace(userID) == "" {
+			return errEmptyUserID
+		}
+	}
+	path := fmt.Sprintf("/portfolios/%s/%s", portfolioID, action)
+	pmr := &portfolioMembersRequest{Members: strings.Join(userIDs, ",")}
+	_, _, err := c.doJSONReqThenSlurpBody("POST", path, pmr)
+	return err
+}
+
+// AddPortfolioMembers shares a portfolio with the users.
+func (c *Client) AddPortfolioMembers(portfolioID string, userIDs ...string) error {
+	return c.changePortfolioMembers("addMembers", portfolioID, userIDs)
+}
+
+func (c *Client) RemovePortfolioMembers(portfolioID string, userIDs ...string) error {
+	return c.changePortfolioMembers("removeMembers", portfolioID, userIDs)
+}
+
+type PortfolioMembershipsPage struct {
+	Memberships []*PortfolioMembership `json:"data"`
+	Err         error
+}
+
+type portfolioMembershipsPager struct {
+	PortfolioMembershipsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListPortfolioMemberships(portfolioID string) (pagesChan chan *PortfolioMembershipsPage, cancelChan chan<- bool, err error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, nil, errEmptyPortfolioID
+	}
+
+	qs := make(url.Values)
+	qs.Set("portfolio", portfolioID)
+	qs.Set("opt_fields", "user,user.name,portfolio,portfolio.name")
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *PortfolioMembershipsPage)
+
+	go c.paginate(fmt.Sprintf("/portfolio_memberships?%s", qs.Encode()), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(portfolioMembershipsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.PortfolioMembershipsPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const portfolioJSON = `{"data": {"id": 31, "name": "Launches", "color": "light-green", "public": true}}`
+
+func TestCreatePortfolio(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	public := true
+	tests := [...]struct {
+		req      *asana.PortfolioRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.PortfolioRequest{Name: "Launches"}, wantErr: true},
+		2: {req: &asana.PortfolioRequest{Workspace: "8"}, wantErr: true},
+		3: {
+			req:      &asana.PortfolioRequest{Workspace: "8", Name: "Launches"},
+			wantBody: `{"data":{"name":"Launches","workspace":"8"}}`,
+		},
+		4: {
+			req: &asana.PortfolioRequest{
+				Workspace: "8", Name: "Launches", Color: "light-green",
+				Members: []string{"7", "11"}, Public: &public,
+			},
+			wantBody: `{"data":{"name":"Launches","color":"light-green","workspace":"8","members":["7","11"],"public":true}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusCreated, body: portfolioJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		portfolio, err := client.CreatePortfolio(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			if len(be.reqs) != 0 {
+				t.Errorf("#%d: invalid request was sent", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/portfolios"; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+		if portfolio.ID != 31 || portfolio.Name != "Launches" || !portfolio.Public {
+			t.Errorf("#%d: unexpected portfolio: %+v", i, portfolio)
+		}
+	}
+}
+
+func TestUpdatePortfolio(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	private, public := false, true
+	tests := [...]struct {
+		req      *asana.PortfolioRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.PortfolioRequest{Name: "Launches"}, wantErr: true},
+		2: {req: &asana.PortfolioRequest{PortfolioID: "31", Workspace: "8"}, wantErr: true},
+		3: {
+			// Making a portfolio private must send the false value.
+			req:      &asana.PortfolioRequest{PortfolioID: "31", Public: &private},
+			wantBody: `{"data":{"public":false}}`,
+		},
+		4: {
+			req:      &asana.PortfolioRequest{PortfolioID: " 31 ", Name: "Launches", Public: &public},
+			wantBody: `{"data":{"name":"Launches","public":true}}`,
+		},
+		5: {
+			// Members are managed separately and never sent on updates.
+			req:      &asana.PortfolioRequest{PortfolioID: "31", Color: "none", Members: []string{"7"}},
+			wantBody: `{"data":{"color":"none"}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: portfolioJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		_, err := client.UpdatePortfolio(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "PUT /api/1.0/portfolios/31"; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+	}
+}
+
+func TestListPortfolios(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	if _, _, err := client.ListPortfolios("", "me"); err == nil {
+		t.Errorf("expected an error for an empty workspace")
+	}
+	if _, _, err := client.ListPortfolios("8", " "); err == nil {
+		t.Errorf("expected an error for an empty owner")
+	}
+
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: `{"data": [{"id": 31, "name": "Launches"}],
+			  "next_page": {"offset": "a", "path": "/portfolios?workspace=8&owner=me&offset=a"}}`},
+			{code: http.StatusOK, body: `{"data": [{"id": 32, "name": "Infra"}], "next_page": null}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	pagesChan, _, err := client.ListPortfolios("8", "me")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("unexpected page error: %v", page.Err)
+		}
+		for _, portfolio := range page.Portfolios {
+			names = append(names, portfolio.Name)
+		}
+	}
+	if len(names) != 2 || names[0] != "Launches" || names[1] != "Infra" {
+		t.Errorf("got portfolios %q", names)
+	}
+	if len(be.reqs) != 2 {
+		t.Fatalf("got %d requests want 2", len(be.reqs))
+	}
+	qs := be.reqs[0].URL.Query()
+	if g, w := qs.Get("workspace")+" "+qs.Get("owner"), "8 me"; g != w {
+		t.Errorf("got workspace and owner %q want %q", g, w)
+	}
+	if g, w := be.reqs[1].URL.Query().Get("offset"), "a"; g != w {
+		t.Errorf("got offset %q want %q", g, w)
+	}
+}
+
+func TestAddPortfolioItem(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		req      *asana.PortfolioItemRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.PortfolioItemRequest{ItemID: "5"}, wantErr: true},
+		2: {req: &asana.PortfolioItemRequest{PortfolioID: "31"}, wantErr: true},
+		3: {
+			req:     &asana.PortfolioItemRequest{PortfolioID: "31", ItemID: "5", InsertBefore: "6", InsertAfter: "4"},
+			wantErr: true,
+		},
+		4: {
+			req:      &asana.PortfolioItemRequest{PortfolioID: "31", ItemID: "5"},
+			wantBody: `{"data":{"item":"5"}}`,
+		},
+		5: {
+			req:      &asana.PortfolioItemRequest{PortfolioID: "31", ItemID: "5", InsertAfter: "4"},
+			wantBody: `{"data":{"item":"5","insert_after":"4"}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: `{"data": {}}`}}}
+		client.SetHTTPRoundTripper(be)
+
+		err := client.AddPortfolioItem(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/portfolios/31/addItem"; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+	}
+}
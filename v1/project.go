@@ -15,6 +15,7 @@
 package asana
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -62,6 +63,9 @@ type ProjectRequest struct {
 	Name  string `json:"name,omitempty"`
 	Notes string `json:"notes,omitempty"`
 
+	// HTMLNotes takes precedence over Notes if both are set.
+	HTMLNotes string `json:"html_notes,omitempty"`
+
 	Color  string `json:"color,omitempty"`
 	Layout Layout `json:"layout,omitempty"`
 
@@ -79,14 +83,22 @@ type Project struct {
 	Color    string `json:"color,omitempty"`
 	Archived bool   `json:"archived,omitempty"`
 
+	HTMLNotes string `json:"html_notes,omitempty"`
+
 	Owner      *NamedAndIDdEntity `json:"owner,omitempty"`
 	CreatedAt  *time.Time         `json:"created_at,omitempty"`
-	ModifiedAt *time.Time         `json:"created_at,omitempty"`
+	ModifiedAt *time.Time         `json:"modified_at,omitempty"`
 
 	Workspace *NamedAndIDdEntity `json:"workspace,omitempty"`
 
 	Members   []*NamedAndIDdEntity `json:"members,omitempty"`
 	Followers []*NamedAndIDdEntity `json:"followers,omitempty"`
+
+	CustomFieldSettings []*CustomFieldSetting `json:"custom_field_settings,omitempty"`
+
+	// CurrentStatusUpdate is the latest status update, if any. Only
+	// its ID and title are set, see CurrentStatusForProject for the rest.
+	CurrentStatusUpdate *StatusUpdate `json:"current_status_update,omitempty"`
 }
 
 var (
@@ -184,6 +196,10 @@ func (c *Client) CreateProject(preq *ProjectRequest) (*Project, error) {
 }
 
 func (c *Client) FindProjectByID(projectID string) (*Project, error) {
+	return c.findProjectByID(context.Background(), projectID)
+}
+
+func (c *Client) findProjectByID(ctx context.Context, projectID string) (*Project, error) {
 	projectID = strings.TrimSpace(projectID)
 	if projectID == "" {
 		return nil, errEmptyProjectID
@@ -193,6 +209,7 @@ func (c *Client) FindProjectByID(projectID string) (*Project, error) {
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 	slurp, _, err := c.doAuthReqThenSlurpBody(req)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,293 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProjectDuplicateOption is something of a project
+// to copy over besides its sections and tasks.
+type ProjectDuplicateOption string
+
+const (
+	DuplicateMembers          ProjectDuplicateOption = "members"
+	DuplicateNotes            ProjectDuplicateOption = "notes"
+	DuplicateForms            ProjectDuplicateOption = "forms"
+	DuplicateTaskNotes        ProjectDuplicateOption = "task_notes"
+	DuplicateTaskAssignee     ProjectDuplicateOption = "task_assignee"
+	DuplicateTaskSubtasks     ProjectDuplicateOption = "task_subtasks"
+	DuplicateTaskAttachments  ProjectDuplicateOption = "task_attachments"
+	DuplicateTaskDates        ProjectDuplicateOption = "task_dates"
+	DuplicateTaskDependencies ProjectDuplicateOption = "task_dependencies"
+	DuplicateTaskFollowers    ProjectDuplicateOption = "task_followers"
+	DuplicateTaskTags         ProjectDuplicateOption = "task_tags"
+	DuplicateTaskProjects     ProjectDuplicateOption = "task_projects"
+)
+
+// ScheduleDates shifts the dates of the duplicated tasks so that the
+// project either starts on StartOn or ends on DueOn. Only one can be set.
+type ScheduleDates struct {
+	StartOn *Date `json:"start_on,omitempty"`
+	DueOn   *Date `json:"due_on,omitempty"`
+
+	ShouldSkipWeekends bool `json:"should_skip_weekends"`
+}
+
+type ProjectDuplicateRequest struct {
+	ProjectID string `json:"-"`
+
+	// Name is that of the new project.
+	Name string `json:"name"`
+
+	// TeamID defaults to the team of the original project.
+	TeamID string `json:"team,omitempty"`
+
+	Include []ProjectDuplicateOption `json:"-"`
+
+	// ScheduleDates requires Include to have DuplicateTaskDates.
+	ScheduleDates *ScheduleDates `json:"schedule_dates,omitempty"`
+}
+
+type projectDuplicateBody struct {
+	*ProjectDuplicateRequest
+
+	// Include is sent as a comma separated list.
+	Include string `json:"include,omitempty"`
+}
+
+var (
+	errNilProjectDuplicateRequest = errors.New("expecting a non-nil projectDuplicateRequest")
+	errEmptyDuplicateName         = errors.New("expecting a non-empty name for the duplicate")
+	errBothStartAndDue            = errors.New("only one of StartOn and DueOn can be set")
+	errNeitherStartNorDue         = errors.New("expecting either StartOn or DueOn to be set")
+	errScheduleWithoutDates       = errors.New("scheduling dates requires including the task dates")
+)
+
+func (pdr *ProjectDuplicateRequest) Validate() error {
+	if pdr == nil {
+		return errNilProjectDuplicateRequest
+	}
+	if strings.TrimSpace(pdr.ProjectID) == "" {
+		return errEmptyProjectID
+	}
+	if strings.TrimSpace(pdr.Name) == "" {
+		return errEmptyDuplicateName
+	}
+	if sd := pdr.ScheduleDates; sd != nil {
+		switch {
+		case sd.StartOn != nil && sd.DueOn != nil:
+			return errBothStartAndDue
+		case sd.StartOn == nil && sd.DueOn == nil:
+			return errNeitherStartNorDue
+		}
+		var includesDates bool
+		for _, opt := range pdr.Include {
+			if opt == DuplicateTaskDates {
+				includesDates = true
+			}
+		}
+		if !includesDates {
+			return errScheduleWithoutDates
+		}
+	}
+	return nil
+}
+
+// DuplicateProject starts copying a project. The copy is made
+// asynchronously, use WaitForNewProject to retrieve it once done.
+func (c *Client) DuplicateProject(pdr *ProjectDuplicateRequest) (*Job, error) {
+	if err := pdr.Validate(); err != nil {
+		return nil, err
+	}
+
+	var include []string
+	for _, opt := range pdr.Include {
+		include = append(include, string(opt))
+	}
+	body := &projectDuplicateBody{ProjectDuplicateRequest: pdr, Include: strings.Join(include, ",")}
+
+	path := fmt.Sprintf("/projects/%s/duplicate", strings.TrimSpace(pdr.ProjectID))
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, body)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutJobFromData(slurp)
+}
+
+// TemplateDate is a date that must be provided when
+// instantiating a template e.g. the project's start date.
+type TemplateDate struct {
+	GID         string `json:"gid"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// TemplateRole is a role of the template that its
+// tasks are assigned to, e.g. "Designer".
+type TemplateRole struct {
+	GID  string `json:"gid"`
+	Name string `json:"name,omitempty"`
+}
+
+type ProjectTemplate struct {
+	ID              int64  `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Description     string `json:"description,omitempty"`
+	HTMLDescription string `json:"html_description,omitempty"`
+	Color           string `json:"color,omitempty"`
+
+	Owner *NamedAndIDdEntity `json:"owner,omitempty"`
+	Team  *NamedAndIDdEntity `json:"team,omitempty"`
+
+	Public bool `json:"public,omitempty"`
+
+	RequestedDates []*TemplateDate `json:"requested_dates,omitempty"`
+	RequestedRoles []*TemplateRole `json:"requested_roles,omitempty"`
+}
+
+var errEmptyProjectTemplateID = errors.New("expecting a non-empty projectTemplateID")
+
+type projectTemplateWrap struct {
+	ProjectTemplate *ProjectTemplate `json:"data"`
+}
+
+func (c *Client) FindProjectTemplateByID(projectTemplateID string) (*ProjectTemplate, error) {
+	projectTemplateID = strings.TrimSpace(projectTemplateID)
+	if projectTemplateID == "" {
+		return nil, errEmptyProjectTemplateID
+	}
+	fullURL := fmt.Sprintf("%s/project_templates/%s", baseURL, projectTemplateID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	ptw := new(projectTemplateWrap)
+	if err := json.Unmarshal(slurp, ptw); err != nil {
+		return nil, err
+	}
+	return ptw.ProjectTemplate, nil
+}
+
+type ProjectTemplatesPage struct {
+	ProjectTemplates []*ProjectTemplate `json:"data"`
+	Err              error
+}
+
+type projectTemplatesPager struct {
+	ProjectTemplatesPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+var errEmptyTemplateScope = errors.New("expecting either a workspace or a team")
+
+// ListProjectTemplates lists the project templates of a team if teamID is
+// set, otherwise those of the workspace.
+func (c *Client) ListProjectTemplates(workspaceID, teamID string) (pagesChan chan *ProjectTemplatesPage, cancelChan chan<- bool, err error) {
+	qs := make(url.Values)
+	if teamID = strings.TrimSpace(teamID); teamID != "" {
+		qs.Set("team", teamID)
+	} else if workspaceID = strings.TrimSpace(workspaceID); workspaceID != "" {
+		qs.Set("workspace", workspaceID)
+	} else {
+		return nil, nil, errEmptyTemplateScope
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *ProjectTemplatesPage)
+
+	go c.paginate(fmt.Sprintf("/project_templates?%s", qs.Encode()), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(projectTemplatesPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.ProjectTemplatesPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
+
+// RequestedDate is the value given for one of a template's RequestedDates.
+type RequestedDate struct {
+	GID   string `json:"gid"`
+	Value Date   `json:"value"`
+}
+
+// RequestedRoleAssignment assigns a user to one of a template's RequestedRoles.
+type RequestedRoleAssignment struct {
+	GID   string `json:"gid"`
+	Value string `json:"value"`
+}
+
+type ProjectTemplateInstantiation struct {
+	ProjectTemplateID string `json:"-"`
+
+	Name string `json:"name"`
+
+	// TeamID is required if the workspace is an organization.
+	TeamID string `json:"team,omitempty"`
+	Public bool   `json:"public"`
+
+	RequestedDates []*RequestedDate           `json:"requested_dates,omitempty"`
+	RequestedRoles []*RequestedRoleAssignment `json:"requested_roles,omitempty"`
+}
+
+var (
+	errNilProjectTemplateInstantiation = errors.New("expecting a non-nil projectTemplateInstantiation")
+	errEmptyRequestedDateValue         = errors.New("expecting a value for every requested date")
+)
+
+func (pti *ProjectTemplateInstantiation) Validate() error {
+	if pti == nil {
+		return errNilProjectTemplateInstantiation
+	}
+	if strings.TrimSpace(pti.ProjectTemplateID) == "" {
+		return errEmptyProjectTemplateID
+	}
+	if strings.TrimSpace(pti.Name) == "" {
+		return errEmptyDuplicateName
+	}
+	for _, rd := range pti.RequestedDates {
+		if rd == nil || rd.Value.IsZero() {
+			return errEmptyRequestedDateValue
+		}
+	}
+	return nil
+}
+
+// InstantiateProjectTemplate starts creating a project from a template.
+// Use WaitForNewProject to retrieve the project once it's created.
+func (c *Client) InstantiateProjectTemplate(pti *ProjectTemplateInstantiation) (*Job, error) {
+	if err := pti.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/project_templates/%s/instantiateProject", strings.TrimSpace(pti.ProjectTemplateID))
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, pti)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutJobFromData(slurp)
+}
@@ -0,0 +1,261 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const duplicateJobJSON = `{"data": {"id": 80, "resource_subtype": "duplicate_project", "status": "not_started",
+  "new_project": {"id": 90, "name": "Launch copy"}}}`
+
+func TestDuplicateProject(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	monday := asana.NewDate(2017, time.October, 2)
+
+	tests := [...]struct {
+		req      *asana.ProjectDuplicateRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.ProjectDuplicateRequest{Name: "Launch copy"}, wantErr: true},
+		2: {req: &asana.ProjectDuplicateRequest{ProjectID: "9"}, wantErr: true},
+		3: {
+			// Scheduling needs the task dates to be copied.
+			req: &asana.ProjectDuplicateRequest{
+				ProjectID: "9", Name: "Launch copy",
+				ScheduleDates: &asana.ScheduleDates{StartOn: &monday},
+			},
+			wantErr: true,
+		},
+		4: {
+			req: &asana.ProjectDuplicateRequest{
+				ProjectID: "9", Name: "Launch copy",
+				Include:       []asana.ProjectDuplicateOption{asana.DuplicateTaskDates},
+				ScheduleDates: &asana.ScheduleDates{StartOn: &monday, DueOn: &monday},
+			},
+			wantErr: true,
+		},
+		5: {
+			req: &asana.ProjectDuplicateRequest{
+				ProjectID: "9", Name: "Launch copy",
+				Include:       []asana.ProjectDuplicateOption{asana.DuplicateTaskDates},
+				ScheduleDates: &asana.ScheduleDates{},
+			},
+			wantErr: true,
+		},
+		6: {
+			req:      &asana.ProjectDuplicateRequest{ProjectID: " 9 ", Name: "Launch copy"},
+			wantBody: `{"data":{"name":"Launch copy"}}`,
+		},
+		7: {
+			req: &asana.ProjectDuplicateRequest{
+				ProjectID: "9", Name: "Launch copy", TeamID: "4",
+				Include:       []asana.ProjectDuplicateOption{asana.DuplicateMembers, asana.DuplicateTaskDates},
+				ScheduleDates: &asana.ScheduleDates{StartOn: &monday, ShouldSkipWeekends: true},
+			},
+			wantBody: `{"data":{"name":"Launch copy","team":"4","schedule_dates":{"start_on":"2017-10-02","should_skip_weekends":true},"include":"members,task_dates"}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusCreated, body: duplicateJobJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		job, err := client.DuplicateProject(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			if len(be.reqs) != 0 {
+				t.Errorf("#%d: invalid request was sent", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/projects/9/duplicate"; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+		if job.ID != 80 || job.NewProject == nil || job.NewProject.ID != 90 {
+			t.Errorf("#%d: unexpected job: %+v", i, job)
+		}
+	}
+}
+
+func TestFindProjectTemplateByID(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: `{"data": {"id": 70, "name": "Launch plan",
+			  "requested_dates": [{"gid": "1", "name": "Start date"}],
+			  "requested_roles": [{"gid": "2", "name": "Designer"}]}}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	if _, err := client.FindProjectTemplateByID(" "); err == nil {
+		t.Errorf("expected an error for an empty projectTemplateID")
+	}
+
+	template, err := client.FindProjectTemplateByID("70")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := be.reqs[0].URL.Path, "/api/1.0/project_templates/70"; g != w {
+		t.Errorf("got path %q want %q", g, w)
+	}
+	if template.Name != "Launch plan" || len(template.RequestedDates) != 1 || len(template.RequestedRoles) != 1 {
+		t.Errorf("unexpected template: %+v", template)
+	}
+}
+
+func TestListProjectTemplates(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		workspaceID string
+		teamID      string
+		wantErr     bool
+		wantQuery   string
+	}{
+		0: {workspaceID: " ", teamID: "", wantErr: true},
+		1: {workspaceID: "8", wantQuery: "workspace=8"},
+		// The team takes precedence over the workspace.
+		2: {workspaceID: "8", teamID: "4", wantQuery: "team=4"},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{
+			resps: []scriptedResp{
+				{code: http.StatusOK, body: `{"data": [{"id": 70, "name": "Launch plan"}],
+				  "next_page": {"offset": "a", "path": "/project_templates?offset=a"}}`},
+				{code: http.StatusOK, body: `{"data": [{"id": 71, "name": "Retro"}]}`},
+			},
+		}
+		client.SetHTTPRoundTripper(be)
+
+		pagesChan, _, err := client.ListProjectTemplates(tt.workspaceID, tt.teamID)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		var names []string
+		for page := range pagesChan {
+			if page.Err != nil {
+				t.Errorf("#%d: unexpected page error: %v", i, page.Err)
+				continue
+			}
+			for _, template := range page.ProjectTemplates {
+				names = append(names, template.Name)
+			}
+		}
+		if len(names) != 2 || names[0] != "Launch plan" || names[1] != "Retro" {
+			t.Errorf("#%d: got templates %q", i, names)
+		}
+		if g, w := be.reqs[0].URL.RawQuery, tt.wantQuery; g != w {
+			t.Errorf("#%d: got query %q want %q", i, g, w)
+		}
+	}
+}
+
+func TestInstantiateProjectTemplate(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		req      *asana.ProjectTemplateInstantiation
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.ProjectTemplateInstantiation{Name: "Q4 launch"}, wantErr: true},
+		2: {req: &asana.ProjectTemplateInstantiation{ProjectTemplateID: "70"}, wantErr: true},
+		3: {
+			// Every requested date needs a value.
+			req: &asana.ProjectTemplateInstantiation{
+				ProjectTemplateID: "70", Name: "Q4 launch",
+				RequestedDates: []*asana.RequestedDate{{GID: "1"}},
+			},
+			wantErr: true,
+		},
+		4: {
+			req: &asana.ProjectTemplateInstantiation{
+				ProjectTemplateID: "70", Name: "Q4 launch", TeamID: "4",
+				RequestedDates: []*asana.RequestedDate{{GID: "1", Value: asana.NewDate(2017, time.October, 2)}},
+				RequestedRoles: []*asana.RequestedRoleAssignment{{GID: "2", Value: "7"}},
+			},
+			wantBody: `{"data":{"name":"Q4 launch","team":"4","public":false,"requested_dates":[{"gid":"1","value":"2017-10-02"}],"requested_roles":[{"gid":"2","value":"7"}]}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusCreated, body: duplicateJobJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		job, err := client.InstantiateProjectTemplate(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/project_templates/70/instantiateProject"; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+		if job.ID != 80 {
+			t.Errorf("#%d: unexpected job: %+v", i, job)
+		}
+	}
+}
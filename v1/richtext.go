@@ -0,0 +1,708 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RichTextKind is the kind of a node of rich text, as found in
+// the html_notes of tasks and projects and the html_text of stories.
+type RichTextKind string
+
+const (
+	RichTextBody          RichTextKind = "body"
+	RichTextText          RichTextKind = "text"
+	RichTextStrong        RichTextKind = "strong"
+	RichTextEm            RichTextKind = "em"
+	RichTextUnderline     RichTextKind = "u"
+	RichTextStrikethrough RichTextKind = "s"
+	RichTextCode          RichTextKind = "code"
+	RichTextLink          RichTextKind = "a"
+	RichTextBulletList    RichTextKind = "ul"
+	RichTextNumberedList  RichTextKind = "ol"
+	RichTextListItem      RichTextKind = "li"
+
+	// RichTextMention is an @-mention of a user, task, project or any
+	// other object, written as <a data-asana-gid="..."/> in HTML.
+	RichTextMention RichTextKind = "mention"
+)
+
+type RichTextNode struct {
+	Kind RichTextKind
+
+	// Text is only set for nodes of kind RichTextText.
+	Text string
+
+	// Href is only set for nodes of kind RichTextLink.
+	Href string
+
+	// GID is the ID of the object that a RichTextMention refers to.
+	GID string
+
+	Children []*RichTextNode
+}
+
+// RichText creates a body node, the root of every rich text document.
+func RichText(children ...*RichTextNode) *RichTextNode {
+	return &RichTextNode{Kind: RichTextBody, Children: children}
+}
+
+func RichTextTextNode(text string) *RichTextNode {
+	return &RichTextNode{Kind: RichTextText, Text: text}
+}
+
+func RichTextMentionNode(gid string) *RichTextNode {
+	return &RichTextNode{Kind: RichTextMention, GID: gid}
+}
+
+func isRichTextList(kind RichTextKind) bool {
+	return kind == RichTextBulletList || kind == RichTextNumberedList
+}
+
+// ParseRichText parses the HTML of Asana's rich text. Elements outside
+// of the supported subset are dropped but their content is kept.
+func ParseRichText(html string) (*RichTextNode, error) {
+	html = strings.TrimSpace(html)
+	if !strings.HasPrefix(html, "<body") {
+		html = fmt.Sprintf("<body>%s</body>", html)
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(html))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	var root *RichTextNode
+	// Unsupported elements push their parent again so
+	// that their children are adopted by it.
+	var stack []*RichTextNode
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			node := richTextNodeFromElement(tok)
+			if len(stack) == 0 {
+				if node == nil || node.Kind != RichTextBody {
+					return nil, fmt.Errorf("expecting <body> as the root element, got <%s>", tok.Name.Local)
+				}
+				root = node
+				stack = append(stack, node)
+				continue
+			}
+			parent := stack[len(stack)-1]
+			if tok.Name.Local == "br" {
+				parent.appendText("\n")
+			}
+			if node == nil || node.Kind == RichTextBody {
+				stack = append(stack, parent)
+				continue
+			}
+			parent.Children = append(parent.Children, node)
+			stack = append(stack, node)
+
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+
+		case xml.CharData:
+			if len(stack) == 0 {
+				continue
+			}
+			parent := stack[len(stack)-1]
+			text := string(tok)
+			// Whitespace between list items is just formatting.
+			if isRichTextList(parent.Kind) && strings.TrimSpace(text) == "" {
+				continue
+			}
+			parent.appendText(text)
+		}
+	}
+
+	if root == nil {
+		return RichText(), nil
+	}
+	return root, nil
+}
+
+func richTextNodeFromElement(se xml.StartElement) *RichTextNode {
+	switch kind := RichTextKind(strings.ToLower(se.Name.Local)); kind {
+	case RichTextBody, RichTextStrong, RichTextEm, RichTextUnderline, RichTextStrikethrough,
+		RichTextCode, RichTextBulletList, RichTextNumberedList, RichTextListItem:
+		return &RichTextNode{Kind: kind}
+	case RichTextLink:
+		node := &RichTextNode{Kind: RichTextLink}
+		for _, attr := range se.Attr {
+			switch attr.Name.Local {
+			case "href":
+				node.Href = attr.Value
+			case "data-asana-gid":
+				node.Kind = RichTextMention
+				node.GID = attr.Value
+			}
+		}
+		return node
+	case "b":
+		return &RichTextNode{Kind: RichTextStrong}
+	case "i":
+		return &RichTextNode{Kind: RichTextEm}
+	default:
+		return nil
+	}
+}
+
+// appendText adds text to the node, merging it with its last child if
+// that's also text so that adjacent runs don't stay fragmented.
+func (rtn *RichTextNode) appendText(text string) {
+	if text == "" {
+		return
+	}
+	if n := len(rtn.Children); n > 0 && rtn.Children[n-1].Kind == RichTextText {
+		rtn.Children[n-1].Text += text
+		return
+	}
+	rtn.Children = append(rtn.Children, RichTextTextNode(text))
+}
+
+// htmlEscaper unlike xml.EscapeText leaves newlines
+// as they are, which is how Asana expects them.
+var htmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+)
+
+// HTML renders the node in the form accepted by html_notes and html_text.
+func (rtn *RichTextNode) HTML() string {
+	buf := new(bytes.Buffer)
+	rtn.writeHTML(buf)
+	return buf.String()
+}
+
+func (rtn *RichTextNode) writeHTML(buf *bytes.Buffer) {
+	if rtn == nil {
+		return
+	}
+	switch rtn.Kind {
+	case RichTextText:
+		buf.WriteString(htmlEscaper.Replace(rtn.Text))
+		return
+	case RichTextMention:
+		buf.WriteString(`<a data-asana-gid="`)
+		buf.WriteString(htmlEscaper.Replace(rtn.GID))
+		buf.WriteString(`"/>`)
+		return
+	case RichTextLink:
+		buf.WriteString(`<a href="`)
+		buf.WriteString(htmlEscaper.Replace(rtn.Href))
+		buf.WriteString(`">`)
+	default:
+		fmt.Fprintf(buf, "<%s>", rtn.Kind)
+	}
+	for _, child := range rtn.Children {
+		child.writeHTML(buf)
+	}
+	fmt.Fprintf(buf, "</%s>", rtn.Kind)
+}
+
+// PlainText returns the text of the node without any formatting,
+// similar to the notes that Asana derives from html_notes.
+func (rtn *RichTextNode) PlainText() string {
+	buf := new(bytes.Buffer)
+	rtn.writePlainText(buf)
+	return buf.String()
+}
+
+func (rtn *RichTextNode) writePlainText(buf *bytes.Buffer) {
+	if rtn == nil {
+		return
+	}
+	switch rtn.Kind {
+	case RichTextText:
+		buf.WriteString(rtn.Text)
+		return
+	case RichTextMention:
+		if len(rtn.Children) == 0 {
+			fmt.Fprintf(buf, "@%s", rtn.GID)
+			return
+		}
+	case RichTextBulletList, RichTextNumberedList:
+		ensureNewline(buf)
+	}
+	for _, child := range rtn.Children {
+		child.writePlainText(buf)
+	}
+	if rtn.Kind == RichTextListItem {
+		ensureNewline(buf)
+	}
+}
+
+func ensureNewline(buf *bytes.Buffer) {
+	if b := buf.Bytes(); len(b) > 0 && b[len(b)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+}
+
+// Markdown renders the node as Markdown. Underlines, which Markdown lacks,
+// are written as ++text++ and mentions as <@gid>, both of which are
+// understood by MarkdownToRichText.
+func (rtn *RichTextNode) Markdown() string {
+	buf := new(bytes.Buffer)
+	rtn.writeMarkdown(buf, 0)
+	return buf.String()
+}
+
+var markdownDelimiters = map[RichTextKind]string{
+	RichTextStrong:        "**",
+	RichTextEm:            "*",
+	RichTextUnderline:     "++",
+	RichTextStrikethrough: "~~",
+}
+
+func (rtn *RichTextNode) writeMarkdown(buf *bytes.Buffer, depth int) {
+	if rtn == nil {
+		return
+	}
+	switch rtn.Kind {
+	case RichTextText:
+		buf.WriteString(escapeMarkdown(rtn.Text, atLineStart(buf)))
+	case RichTextMention:
+		fmt.Fprintf(buf, "<@%s>", rtn.GID)
+	case RichTextCode:
+		fmt.Fprintf(buf, "`%s`", strings.Replace(rtn.plainChildren(), "`", "'", -1))
+	case RichTextLink:
+		buf.WriteString("[")
+		rtn.writeMarkdownChildren(buf, depth)
+		fmt.Fprintf(buf, "](%s)", markdownLinkDestination(rtn.Href))
+	case RichTextBulletList, RichTextNumberedList:
+		ensureNewline(buf)
+		for i, item := range rtn.Children {
+			if item.Kind != RichTextListItem {
+				continue
+			}
+			buf.WriteString(strings.Repeat("  ", depth))
+			if rtn.Kind == RichTextNumberedList {
+				fmt.Fprintf(buf, "%d. ", i+1)
+			} else {
+				buf.WriteString("- ")
+			}
+			item.writeMarkdownChildren(buf, depth+1)
+			ensureNewline(buf)
+		}
+	default:
+		delim := markdownDelimiters[rtn.Kind]
+		buf.WriteString(delim)
+		rtn.writeMarkdownChildren(buf, depth)
+		buf.WriteString(delim)
+	}
+}
+
+// markdownLinkDestination wraps href in angle brackets if it has
+// characters that would otherwise end the link early, e.g. the
+// parentheses of https://en.wikipedia.org/wiki/Go_(programming_language).
+func markdownLinkDestination(href string) string {
+	if !strings.ContainsAny(href, " ()<>\\") {
+		return href
+	}
+	return "<" + markdownLinkDestinationEscaper.Replace(href) + ">"
+}
+
+var markdownLinkDestinationEscaper = strings.NewReplacer(`\`, `\\`, "<", `\<`, ">", `\>`)
+
+// parseMarkdownLinkDestination parses the "(href)" or "(<href>)" that
+// follows the text of a link, returning the href and the length of the
+// parsed destination, or ok false if s doesn't start with one.
+func parseMarkdownLinkDestination(s string) (href string, n int, ok bool) {
+	if !strings.HasPrefix(s, "(") {
+		return "", 0, false
+	}
+	if !strings.HasPrefix(s, "(<") {
+		if end := strings.IndexByte(s, ')'); end > 0 {
+			return s[1:end], end + 1, true
+		}
+		return "", 0, false
+	}
+	var buf strings.Builder
+	for i := 2; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte(`\<>`, s[i+1]) >= 0 {
+				i++
+			}
+		case '>':
+			if strings.HasPrefix(s[i+1:], ")") {
+				return buf.String(), i + 2, true
+			}
+			return "", 0, false
+		case '<', '\n':
+			return "", 0, false
+		}
+		buf.WriteByte(s[i])
+	}
+	return "", 0, false
+}
+
+func (rtn *RichTextNode) writeMarkdownChildren(buf *bytes.Buffer, depth int) {
+	for _, child := range rtn.Children {
+		child.writeMarkdown(buf, depth)
+	}
+}
+
+func (rtn *RichTextNode) plainChildren() string {
+	buf := new(bytes.Buffer)
+	for _, child := range rtn.Children {
+		child.writePlainText(buf)
+	}
+	return buf.String()
+}
+
+func atLineStart(buf *bytes.Buffer) bool {
+	b := buf.Bytes()
+	return len(b) == 0 || b[len(b)-1] == '\n'
+}
+
+var orderedListMarkerRegexp = regexp.MustCompile(`^(\s*\d+)\.`)
+
+// escapeMarkdown backslash escapes the characters of text that
+// MarkdownToRichText would otherwise read as formatting.
+func escapeMarkdown(text string, lineStart bool) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		buf := new(bytes.Buffer)
+		runes := []rune(line)
+		for j, r := range runes {
+			next := rune(0)
+			if j+1 < len(runes) {
+				next = runes[j+1]
+			}
+			switch {
+			case strings.ContainsRune("\\*_`[]", r),
+				(r == '~' || r == '+') && next == r,
+				r == '<' && next == '@':
+				buf.WriteByte('\\')
+			}
+			buf.WriteRune(r)
+		}
+		line = buf.String()
+
+		if i > 0 || lineStart {
+			trimmed := strings.TrimLeft(line, " ")
+			if strings.HasPrefix(trimmed, "- ") {
+				line = line[:len(line)-len(trimmed)] + "\\" + trimmed
+			} else if loc := orderedListMarkerRegexp.FindStringSubmatchIndex(line); loc != nil {
+				line = line[:loc[3]] + "\\" + line[loc[3]:]
+			}
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+var markdownListItemRegexp = regexp.MustCompile(`^( *)(-|\*|\d+\.) +(.*)$`)
+
+// MarkdownToRichText converts Markdown, as produced by RichTextNode.Markdown,
+// to rich text. Besides the inline formatting and lists that rich text
+// supports, anything else e.g. headings is kept as plain text.
+func MarkdownToRichText(markdown string) *RichTextNode {
+	body := RichText()
+
+	type openList struct {
+		indent int
+		list   *RichTextNode
+	}
+	var lists []*openList
+	var paragraph []string
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		for _, node := range parseMarkdownInline(strings.Join(paragraph, "\n")) {
+			if node.Kind == RichTextText {
+				body.appendText(node.Text)
+			} else {
+				body.Children = append(body.Children, node)
+			}
+		}
+		paragraph = nil
+	}
+
+	markdown = strings.Replace(markdown, "\r\n", "\n", -1)
+	markdown = strings.TrimSuffix(markdown, "\n")
+	for _, line := range strings.Split(markdown, "\n") {
+		m := markdownListItemRegexp.FindStringSubmatch(line)
+		if m == nil {
+			lists = nil
+			paragraph = append(paragraph, line)
+			continue
+		}
+
+		indent, marker, content := len(m[1]), m[2], m[3]
+		kind := RichTextBulletList
+		if marker != "-" && marker != "*" {
+			kind = RichTextNumberedList
+		}
+
+		for len(lists) > 0 && lists[len(lists)-1].indent > indent {
+			lists = lists[:len(lists)-1]
+		}
+		if n := len(lists); n > 0 && lists[n-1].indent == indent && lists[n-1].list.Kind != kind {
+			lists = lists[:n-1]
+		}
+
+		if n := len(lists); n == 0 || lists[n-1].indent < indent {
+			list := &RichTextNode{Kind: kind}
+			if n == 0 {
+				flushParagraph()
+				body.Children = append(body.Children, list)
+			} else {
+				// Nest the list within the last item of its parent.
+				items := lists[n-1].list.Children
+				lastItem := items[len(items)-1]
+				lastItem.Children = append(lastItem.Children, list)
+			}
+			lists = append(lists, &openList{indent: indent, list: list})
+		}
+
+		list := lists[len(lists)-1].list
+		item := &RichTextNode{Kind: RichTextListItem, Children: parseMarkdownInline(content)}
+		list.Children = append(list.Children, item)
+	}
+	flushParagraph()
+
+	return body
+}
+
+var markdownMentionRegexp = regexp.MustCompile(`^<@(\d+)>`)
+
+// maxMarkdownNesting bounds how deeply links and emphases can be nested,
+// past which their markers are literal text. Without it, a long run of
+// unclosed markers would take quadratic time to parse.
+const maxMarkdownNesting = 32
+
+// markdownSpecials are the bytes that can start or end Markdown
+// formatting, every other byte is literal text.
+const markdownSpecials = "\\`<[]*_~+"
+
+type markdownInlineParser struct {
+	s string
+	i int
+
+	// depth is how many links and emphases are currently open.
+	depth int
+
+	// unclosed are the delimiters that were already scanned to the end
+	// of s without being closed, which any later opener can't be either.
+	unclosed map[string]bool
+}
+
+func parseMarkdownInline(s string) []*RichTextNode {
+	p := &markdownInlineParser{s: s, unclosed: make(map[string]bool)}
+	nodes, _ := p.parse("")
+	return nodes
+}
+
+// parse consumes s until closer, or its end if closer
+// is empty, and reports whether closer was found.
+func (p *markdownInlineParser) parse(closer string) (nodes []*RichTextNode, closed bool) {
+	parent := new(RichTextNode)
+	// Literal text is accumulated and only added to parent
+	// in one go, ahead of the next node that isn't text.
+	var text strings.Builder
+	flush := func() {
+		parent.appendText(text.String())
+		text.Reset()
+	}
+	adopt := func(children []*RichTextNode) {
+		for _, child := range children {
+			if child.Kind == RichTextText {
+				text.WriteString(child.Text)
+			} else {
+				flush()
+				parent.Children = append(parent.Children, child)
+			}
+		}
+	}
+	for p.i < len(p.s) {
+		rest := p.s[p.i:]
+		if closer != "" && strings.HasPrefix(rest, closer) && !(closer == "*" && strings.HasPrefix(rest, "**")) &&
+			!(closer == "_" && startsWithWordRune(rest[1:])) {
+			p.i += len(closer)
+			flush()
+			return parent.Children, true
+		}
+
+		switch {
+		case rest[0] == '\\' && len(rest) > 1:
+			text.WriteString(rest[1:2])
+			p.i += 2
+
+		case rest[0] == '`':
+			end := strings.IndexByte(rest[1:], '`')
+			if end < 0 {
+				text.WriteString("`")
+				p.i++
+				continue
+			}
+			code := &RichTextNode{Kind: RichTextCode}
+			code.appendText(rest[1 : 1+end])
+			flush()
+			parent.Children = append(parent.Children, code)
+			p.i += end + 2
+
+		case markdownMentionRegexp.MatchString(rest):
+			m := markdownMentionRegexp.FindStringSubmatch(rest)
+			flush()
+			parent.Children = append(parent.Children, RichTextMentionNode(m[1]))
+			p.i += len(m[0])
+
+		case rest[0] == '[' && p.depth < maxMarkdownNesting && !p.unclosed["]"]:
+			p.i++
+			p.depth++
+			children, ok := p.parse("]")
+			p.depth--
+			if !ok {
+				p.unclosed["]"] = true
+			}
+			if ok {
+				if href, n, isLink := parseMarkdownLinkDestination(p.s[p.i:]); isLink {
+					flush()
+					parent.Children = append(parent.Children, &RichTextNode{Kind: RichTextLink, Href: href, Children: children})
+					p.i += n
+					continue
+				}
+			}
+			text.WriteString("[")
+			adopt(children)
+			if ok {
+				text.WriteString("]")
+			}
+
+		default:
+			kind, delim := markdownOpener(rest)
+			if delim == "_" && endsWithWordRune(p.s[:p.i]) {
+				// Underscores within words e.g. my_var_name are literal.
+				delim = ""
+			}
+			if delim == "" || p.unclosed[delim] || p.depth >= maxMarkdownNesting {
+				if delim == "" {
+					delim = rest[:1]
+				}
+				// Consume the whole run of literal text at once.
+				n := len(delim)
+				if end := strings.IndexAny(rest[n:], markdownSpecials); end >= 0 {
+					n += end
+				} else {
+					n = len(rest)
+				}
+				text.WriteString(rest[:n])
+				p.i += n
+				continue
+			}
+			p.i += len(delim)
+			p.depth++
+			children, ok := p.parse(delim)
+			p.depth--
+			if !ok {
+				p.unclosed[delim] = true
+			}
+			if ok && len(children) > 0 {
+				flush()
+				parent.Children = append(parent.Children, &RichTextNode{Kind: kind, Children: children})
+				continue
+			}
+			// Unmatched or empty delimiters are literal text.
+			text.WriteString(delim)
+			adopt(children)
+			if ok {
+				text.WriteString(delim)
+			}
+		}
+	}
+	flush()
+	return parent.Children, closer == ""
+}
+
+func markdownOpener(s string) (RichTextKind, string) {
+	for _, delim := range []string{"**", "~~", "++"} {
+		if strings.HasPrefix(s, delim) {
+			switch delim {
+			case "**":
+				return RichTextStrong, delim
+			case "~~":
+				return RichTextStrikethrough, delim
+			default:
+				return RichTextUnderline, delim
+			}
+		}
+	}
+	if s[0] == '*' || s[0] == '_' {
+		return RichTextEm, s[:1]
+	}
+	return "", ""
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func startsWithWordRune(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return isWordRune(r)
+}
+
+func endsWithWordRune(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return isWordRune(r)
+}
+
+// HTMLToMarkdown converts rich text HTML e.g. a task's HTMLNotes to Markdown.
+func HTMLToMarkdown(html string) (string, error) {
+	rtn, err := ParseRichText(html)
+	if err != nil {
+		return "", err
+	}
+	return rtn.Markdown(), nil
+}
+
+// MarkdownToHTML converts Markdown to HTML suitable for html_notes and html_text.
+func MarkdownToHTML(markdown string) string {
+	return MarkdownToRichText(markdown).HTML()
+}
+
+// RichNotes parses the task's HTMLNotes.
+func (t *Task) RichNotes() (*RichTextNode, error) {
+	return ParseRichText(t.HTMLNotes)
+}
+
+func (p *Project) RichNotes() (*RichTextNode, error) {
+	return ParseRichText(p.HTMLNotes)
+}
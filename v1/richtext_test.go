@@ -0,0 +1,132 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestRichTextMarkdownRoundTrip(t *testing.T) {
+	tests := [...]struct {
+		markdown string
+		wantHTML string
+
+		// wantMarkdown is set if converting back doesn't
+		// give markdown, e.g. because it wasn't escaped.
+		wantMarkdown string
+	}{
+		0: {
+			markdown: "Build **failed** on `master`, see [the logs](https://ci.example.com/1).",
+			wantHTML: `<body>Build <strong>failed</strong> on <code>master</code>, see <a href="https://ci.example.com/1">the logs</a>.</body>`,
+		},
+		1: {
+			markdown: "Owners:\n- <@12345> for *docs*\n  1. ~~draft~~\n  2. ++review++\n- nobody\nThanks",
+			wantHTML: `<body>Owners:<ul><li><a data-asana-gid="12345"/> for <em>docs</em><ol><li><s>draft</s></li><li><u>review</u></li></ol></li><li>nobody</li></ul>Thanks</body>`,
+		},
+		2: {
+			// Formatting characters that aren't markup stay as text.
+			markdown: "2\\*3 is \\<@6> and snake\\_case\n\\- not a list",
+			wantHTML: "<body>2*3 is &lt;@6&gt; and snake_case\n- not a list</body>",
+		},
+		3: {
+			// Escaping looks at the rune after multibyte ones.
+			markdown: "Café ~~menu~~ é\\~~ and ü\\++",
+			wantHTML: "<body>Café <s>menu</s> é~~ and ü++</body>",
+		},
+		4: {
+			markdown: "*a* *b* and a lone \\* then **c**",
+			wantHTML: "<body><em>a</em> <em>b</em> and a lone * then <strong>c</strong></body>",
+		},
+		5: {
+			// Destinations with parentheses or spaces are wrapped in angle brackets.
+			markdown: "See [Go](<https://en.wikipedia.org/wiki/Go_(programming_language)>) and [notes](<file:///my notes\\<1\\>.txt>).",
+			wantHTML: `<body>See <a href="https://en.wikipedia.org/wiki/Go_(programming_language)">Go</a> and <a href="file:///my notes&lt;1&gt;.txt">notes</a>.</body>`,
+		},
+		6: {
+			// Underscores within words don't start emphasis.
+			markdown:     "Rename my_var_name to _new_name_ or snake_case_",
+			wantHTML:     "<body>Rename my_var_name to <em>new_name</em> or snake_case_</body>",
+			wantMarkdown: "Rename my\\_var\\_name to *new\\_name* or snake\\_case\\_",
+		},
+	}
+
+	for i, tt := range tests {
+		html := asana.MarkdownToHTML(tt.markdown)
+		if html != tt.wantHTML {
+			t.Errorf("#%d: html:\ngot:  %s\nwant: %s", i, html, tt.wantHTML)
+			continue
+		}
+		markdown, err := asana.HTMLToMarkdown(html)
+		if err != nil {
+			t.Errorf("#%d: parsing html: %v", i, err)
+			continue
+		}
+		wantMarkdown := tt.wantMarkdown
+		if wantMarkdown == "" {
+			wantMarkdown = tt.markdown
+		}
+		if markdown != wantMarkdown {
+			t.Errorf("#%d: markdown:\ngot:  %q\nwant: %q", i, markdown, wantMarkdown)
+		}
+	}
+}
+
+func TestParseRichText(t *testing.T) {
+	html := `<body>Hi <a data-asana-gid="42">@Ann</a>,<br><h1>Plan</h1>
+<ul>
+  <li><b>one</b></li>
+</ul></body>`
+	rtn, err := asana.ParseRichText(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rtn.PlainText(), "Hi @Ann,\nPlan\none\n"; got != want {
+		t.Errorf("plain text:\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := rtn.HTML(), "<body>Hi <a data-asana-gid=\"42\"/>,\nPlan\n<ul><li><strong>one</strong></li></ul></body>"; got != want {
+		t.Errorf("html:\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestMarkdownToRichTextLongInput(t *testing.T) {
+	// Markers that are never closed are literal text. These
+	// used to take quadratic time, and far too long to parse.
+	tests := [...]string{
+		0: strings.Repeat("[", 200000),
+		1: "_" + strings.Repeat("never closed ", 20000),
+		2: strings.Repeat("3 < 4 ] ", 25000),
+		3: strings.Repeat("*", 200000),
+		4: strings.Repeat("[a](<b ", 40000),
+	}
+
+	for i, markdown := range tests {
+		done := make(chan string, 1)
+		go func() {
+			done <- asana.MarkdownToRichText(markdown).PlainText()
+		}()
+		select {
+		case text := <-done:
+			if text != markdown {
+				t.Errorf("#%d: the text of the markdown was altered", i)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("#%d: parsing %d bytes took too long", i, len(markdown))
+		}
+	}
+}
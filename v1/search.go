@@ -0,0 +1,260 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SortField string
+
+const (
+	SortByDueDate     SortField = "due_date"
+	SortByCreatedAt   SortField = "created_at"
+	SortByCompletedAt SortField = "completed_at"
+	SortByLikes       SortField = "likes"
+	SortByModifiedAt  SortField = "modified_at"
+)
+
+const dateLayout = "2006-01-02"
+
+// TaskSearch builds a query for the workspace task search endpoint.
+// Its methods can be chained, for example:
+//
+//	asana.NewTaskSearch(workspaceID).Text("release").AssigneeAny("me").Completed(false)
+//
+// Different filters are combined with AND by the API. Each filter is a
+// single parameter so calling the same method again replaces its value,
+// the last call winning; pass several IDs to one call instead.
+type TaskSearch struct {
+	workspaceID string
+	params      url.Values
+}
+
+func NewTaskSearch(workspaceID string) *TaskSearch {
+	return &TaskSearch{
+		workspaceID: strings.TrimSpace(workspaceID),
+		params:      make(url.Values),
+	}
+}
+
+func (ts *TaskSearch) set(key, value string) *TaskSearch {
+	ts.params.Set(key, value)
+	return ts
+}
+
+func (ts *TaskSearch) setIDs(key string, ids []string) *TaskSearch {
+	return ts.set(key, strings.Join(ids, ","))
+}
+
+func (ts *TaskSearch) setDate(key string, t time.Time) *TaskSearch {
+	return ts.set(key, t.Format(dateLayout))
+}
+
+func (ts *TaskSearch) setTime(key string, t time.Time) *TaskSearch {
+	return ts.set(key, t.UTC().Format(time.RFC3339))
+}
+
+// Text matches tasks whose name or notes contain text.
+func (ts *TaskSearch) Text(text string) *TaskSearch {
+	return ts.set("text", text)
+}
+
+// AssigneeAny matches tasks assigned to any of the users,
+// who can be referred to by ID, email or "me".
+func (ts *TaskSearch) AssigneeAny(userIDs ...string) *TaskSearch {
+	return ts.setIDs("assignee.any", userIDs)
+}
+
+func (ts *TaskSearch) AssigneeNot(userIDs ...string) *TaskSearch {
+	return ts.setIDs("assignee.not", userIDs)
+}
+
+func (ts *TaskSearch) ProjectsAny(projectIDs ...string) *TaskSearch {
+	return ts.setIDs("projects.any", projectIDs)
+}
+
+func (ts *TaskSearch) ProjectsNot(projectIDs ...string) *TaskSearch {
+	return ts.setIDs("projects.not", projectIDs)
+}
+
+func (ts *TaskSearch) ProjectsAll(projectIDs ...string) *TaskSearch {
+	return ts.setIDs("projects.all", projectIDs)
+}
+
+func (ts *TaskSearch) SectionsAny(sectionIDs ...string) *TaskSearch {
+	return ts.setIDs("sections.any", sectionIDs)
+}
+
+func (ts *TaskSearch) SectionsNot(sectionIDs ...string) *TaskSearch {
+	return ts.setIDs("sections.not", sectionIDs)
+}
+
+func (ts *TaskSearch) SectionsAll(sectionIDs ...string) *TaskSearch {
+	return ts.setIDs("sections.all", sectionIDs)
+}
+
+func (ts *TaskSearch) TagsAny(tagIDs ...string) *TaskSearch {
+	return ts.setIDs("tags.any", tagIDs)
+}
+
+func (ts *TaskSearch) TagsNot(tagIDs ...string) *TaskSearch {
+	return ts.setIDs("tags.not", tagIDs)
+}
+
+func (ts *TaskSearch) TagsAll(tagIDs ...string) *TaskSearch {
+	return ts.setIDs("tags.all", tagIDs)
+}
+
+// DueOn matches tasks due on the date, ignoring the time of day in t.
+func (ts *TaskSearch) DueOn(t time.Time) *TaskSearch {
+	return ts.setDate("due_on", t)
+}
+
+func (ts *TaskSearch) DueOnBefore(t time.Time) *TaskSearch {
+	return ts.setDate("due_on.before", t)
+}
+
+func (ts *TaskSearch) DueOnAfter(t time.Time) *TaskSearch {
+	return ts.setDate("due_on.after", t)
+}
+
+func (ts *TaskSearch) DueAtBefore(t time.Time) *TaskSearch {
+	return ts.setTime("due_at.before", t)
+}
+
+func (ts *TaskSearch) DueAtAfter(t time.Time) *TaskSearch {
+	return ts.setTime("due_at.after", t)
+}
+
+func (ts *TaskSearch) CreatedOnBefore(t time.Time) *TaskSearch {
+	return ts.setDate("created_on.before", t)
+}
+
+func (ts *TaskSearch) CreatedOnAfter(t time.Time) *TaskSearch {
+	return ts.setDate("created_on.after", t)
+}
+
+func (ts *TaskSearch) CreatedAtBefore(t time.Time) *TaskSearch {
+	return ts.setTime("created_at.before", t)
+}
+
+func (ts *TaskSearch) CreatedAtAfter(t time.Time) *TaskSearch {
+	return ts.setTime("created_at.after", t)
+}
+
+func (ts *TaskSearch) ModifiedOnBefore(t time.Time) *TaskSearch {
+	return ts.setDate("modified_on.before", t)
+}
+
+func (ts *TaskSearch) ModifiedOnAfter(t time.Time) *TaskSearch {
+	return ts.setDate("modified_on.after", t)
+}
+
+func (ts *TaskSearch) ModifiedAtBefore(t time.Time) *TaskSearch {
+	return ts.setTime("modified_at.before", t)
+}
+
+func (ts *TaskSearch) ModifiedAtAfter(t time.Time) *TaskSearch {
+	return ts.setTime("modified_at.after", t)
+}
+
+func (ts *TaskSearch) Completed(completed bool) *TaskSearch {
+	return ts.set("completed", strconv.FormatBool(completed))
+}
+
+func (ts *TaskSearch) IsSubtask(isSubtask bool) *TaskSearch {
+	return ts.set("is_subtask", strconv.FormatBool(isSubtask))
+}
+
+func customFieldKey(fieldID int64, predicate string) string {
+	return fmt.Sprintf("custom_fields.%d.%s", fieldID, predicate)
+}
+
+// CustomFieldIsSet matches tasks for which the
+// custom field has a value, or doesn't if isSet is false.
+func (ts *TaskSearch) CustomFieldIsSet(fieldID int64, isSet bool) *TaskSearch {
+	return ts.set(customFieldKey(fieldID, "is_set"), strconv.FormatBool(isSet))
+}
+
+// CustomFieldValue matches tasks whose custom field equals value. For enum
+// custom fields value is the enum option's ID, for text fields its content.
+func (ts *TaskSearch) CustomFieldValue(fieldID int64, value string) *TaskSearch {
+	return ts.set(customFieldKey(fieldID, "value"), value)
+}
+
+func (ts *TaskSearch) CustomFieldStartsWith(fieldID int64, prefix string) *TaskSearch {
+	return ts.set(customFieldKey(fieldID, "starts_with"), prefix)
+}
+
+func (ts *TaskSearch) CustomFieldEndsWith(fieldID int64, suffix string) *TaskSearch {
+	return ts.set(customFieldKey(fieldID, "ends_with"), suffix)
+}
+
+func (ts *TaskSearch) CustomFieldContains(fieldID int64, substr string) *TaskSearch {
+	return ts.set(customFieldKey(fieldID, "contains"), substr)
+}
+
+func (ts *TaskSearch) CustomFieldLessThan(fieldID int64, value float64) *TaskSearch {
+	return ts.set(customFieldKey(fieldID, "less_than"), strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func (ts *TaskSearch) CustomFieldGreaterThan(fieldID int64, value float64) *TaskSearch {
+	return ts.set(customFieldKey(fieldID, "greater_than"), strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func (ts *TaskSearch) SortBy(field SortField) *TaskSearch {
+	return ts.set("sort_by", string(field))
+}
+
+func (ts *TaskSearch) SortAscending(ascending bool) *TaskSearch {
+	return ts.set("sort_ascending", strconv.FormatBool(ascending))
+}
+
+// Limit caps the number of results per page.
+func (ts *TaskSearch) Limit(n int) *TaskSearch {
+	return ts.set("limit", strconv.Itoa(n))
+}
+
+var errNilTaskSearch = errors.New("expecting a non-nil taskSearch")
+
+func (ts *TaskSearch) Validate() error {
+	if ts == nil {
+		return errNilTaskSearch
+	}
+	if ts.workspaceID == "" {
+		return errEmptyWorkspace
+	}
+	return nil
+}
+
+// SearchTasks runs the query against the workspace's task search endpoint.
+// Unlike ListMyTasks, it can filter on almost every attribute of a task.
+func (c *Client) SearchTasks(ts *TaskSearch) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
+	if err := ts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf("/workspaces/%s/tasks/search", ts.workspaceID)
+	if len(ts.params) > 0 {
+		path = fmt.Sprintf("%s?%s", path, ts.params.Encode())
+	}
+	return c.doTasksPaging(path)
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+// cannedBackend replies to every request with the same
+// JSON body while recording the requests that it received.
+type cannedBackend struct {
+	sync.Mutex
+	body string
+	reqs []*http.Request
+}
+
+var _ http.RoundTripper = (*cannedBackend)(nil)
+
+func (cb *cannedBackend) RoundTrip(req *http.Request) (*http.Response, error) {
+	cb.Lock()
+	cb.reqs = append(cb.reqs, req)
+	cb.Unlock()
+	return makeResp("200 OK", http.StatusOK, ioutil.NopCloser(strings.NewReader(cb.body))), nil
+}
+
+func TestSearchTasks(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &cannedBackend{body: `{"data": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}]}`}
+	client.SetHTTPRoundTripper(be)
+
+	due := time.Date(2017, time.March, 5, 0, 0, 0, 0, time.UTC)
+	search := asana.NewTaskSearch("1331").
+		Text("release notes").
+		AssigneeAny("me", "1234").
+		ProjectsAny("42").
+		DueOnBefore(due).
+		Completed(false).
+		CustomFieldGreaterThan(77, 2.5).
+		SortBy(asana.SortByDueDate).
+		SortAscending(true)
+
+	pagesChan, _, err := client.SearchTasks(search)
+	if err != nil {
+		t.Fatalf("searching: %v", err)
+	}
+	var tasks []*asana.Task
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			t.Fatalf("page err: %v", err)
+		}
+		tasks = append(tasks, page.Tasks...)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("got %d tasks want 2", len(tasks))
+	}
+
+	if len(be.reqs) != 1 {
+		t.Fatalf("got %d requests want 1", len(be.reqs))
+	}
+	req := be.reqs[0]
+	if got, want := req.URL.Path, "/api/1.0/workspaces/1331/tasks/search"; got != want {
+		t.Errorf("path: got %q want %q", got, want)
+	}
+	wantQuery := map[string]string{
+		"text":                          "release notes",
+		"assignee.any":                  "me,1234",
+		"projects.any":                  "42",
+		"due_on.before":                 "2017-03-05",
+		"completed":                     "false",
+		"custom_fields.77.greater_than": "2.5",
+		"sort_by":                       "due_date",
+		"sort_ascending":                "true",
+	}
+	query := req.URL.Query()
+	for key, want := range wantQuery {
+		if got := query.Get(key); got != want {
+			t.Errorf("%q: got %q want %q", key, got, want)
+		}
+	}
+}
+
+func TestTaskSearchLastCallWins(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &cannedBackend{body: `{"data": []}`}
+	client.SetHTTPRoundTripper(be)
+
+	search := asana.NewTaskSearch("1331").
+		TagsAny("1").
+		TagsAny("2", "3").
+		Text("draft").
+		Text("final")
+	pagesChan, _, err := client.SearchTasks(search)
+	if err != nil {
+		t.Fatalf("searching: %v", err)
+	}
+	for range pagesChan {
+	}
+
+	query := be.reqs[0].URL.Query()
+	wantQuery := map[string][]string{
+		"tags.any": {"2,3"},
+		"text":     {"final"},
+	}
+	for key, want := range wantQuery {
+		if got := query[key]; len(got) != len(want) || got[0] != want[0] {
+			t.Errorf("%q: got %q want %q", key, got, want)
+		}
+	}
+}
+
+func TestSearchTasksValidation(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	if _, _, err := client.SearchTasks(nil); err == nil {
+		t.Errorf("nil search: wanted non-nil error")
+	}
+	if _, _, err := client.SearchTasks(asana.NewTaskSearch("  ")); err == nil {
+		t.Errorf("blank workspace: wanted non-nil error")
+	}
+}
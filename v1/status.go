@@ -0,0 +1,230 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type StatusType string
+
+const (
+	StatusUpdateOnTrack  StatusType = "on_track"
+	StatusUpdateAtRisk   StatusType = "at_risk"
+	StatusUpdateOffTrack StatusType = "off_track"
+	StatusUpdateOnHold   StatusType = "on_hold"
+	StatusUpdateComplete StatusType = "complete"
+)
+
+func (st StatusType) valid() bool {
+	switch st {
+	case StatusUpdateOnTrack, StatusUpdateAtRisk, StatusUpdateOffTrack, StatusUpdateOnHold, StatusUpdateComplete:
+		return true
+	default:
+		return false
+	}
+}
+
+// StatusUpdate reports on the health of a project, portfolio or goal.
+type StatusUpdate struct {
+	ID    int64  `json:"id,omitempty"`
+	Title string `json:"title,omitempty"`
+
+	StatusType StatusType `json:"status_type,omitempty"`
+
+	Text string `json:"text,omitempty"`
+
+	// HTMLText is the rich text version of Text, see ParseRichText.
+	HTMLText string `json:"html_text,omitempty"`
+
+	Parent     *NamedAndIDdEntity `json:"parent,omitempty"`
+	Author     *NamedAndIDdEntity `json:"author,omitempty"`
+	CreatedBy  *NamedAndIDdEntity `json:"created_by,omitempty"`
+	CreatedAt  *time.Time         `json:"created_at,omitempty"`
+	ModifiedAt *time.Time         `json:"modified_at,omitempty"`
+}
+
+type StatusUpdateRequest struct {
+	// ParentID is the ID of the project, portfolio or goal.
+	ParentID string `json:"parent"`
+
+	Title      string     `json:"title,omitempty"`
+	StatusType StatusType `json:"status_type"`
+
+	// Only one of Text and HTMLText should be set.
+	// MarkdownToHTML can be used to write HTMLText in Markdown.
+	Text     string `json:"text,omitempty"`
+	HTMLText string `json:"html_text,omitempty"`
+}
+
+var (
+	errNilStatusUpdateRequest = errors.New("expecting a non-nil statusUpdateRequest")
+	errEmptyStatusUpdateID    = errors.New("expecting a non-empty statusUpdateID")
+	errEmptyStatusText        = errors.New("expecting either text or htmlText to be set")
+)
+
+func (sur *StatusUpdateRequest) Validate() error {
+	if sur == nil {
+		return errNilStatusUpdateRequest
+	}
+	if strings.TrimSpace(sur.ParentID) == "" {
+		return errEmptyParentID
+	}
+	if !sur.StatusType.valid() {
+		return fmt.Errorf("unknown status type %q", sur.StatusType)
+	}
+	if strings.TrimSpace(sur.Text) == "" && strings.TrimSpace(sur.HTMLText) == "" {
+		return errEmptyStatusText
+	}
+	return nil
+}
+
+type statusUpdateWrap struct {
+	StatusUpdate *StatusUpdate `json:"data"`
+}
+
+func parseOutStatusUpdateFromData(blob []byte) (*StatusUpdate, error) {
+	suw := new(statusUpdateWrap)
+	if err := json.Unmarshal(blob, suw); err != nil {
+		return nil, err
+	}
+	return suw.StatusUpdate, nil
+}
+
+// CreateStatusUpdate publishes a status update, which
+// becomes the current status of its parent.
+func (c *Client) CreateStatusUpdate(sur *StatusUpdateRequest) (*StatusUpdate, error) {
+	if err := sur.Validate(); err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", "/status_updates", sur)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutStatusUpdateFromData(slurp)
+}
+
+func (c *Client) FindStatusUpdateByID(statusUpdateID string) (*StatusUpdate, error) {
+	statusUpdateID = strings.TrimSpace(statusUpdateID)
+	if statusUpdateID == "" {
+		return nil, errEmptyStatusUpdateID
+	}
+	fullURL := fmt.Sprintf("%s/status_updates/%s", baseURL, statusUpdateID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutStatusUpdateFromData(slurp)
+}
+
+type projectCurrentStatusWrap struct {
+	Project struct {
+		CurrentStatusUpdate *NamedAndIDdEntity `json:"current_status_update"`
+	} `json:"data"`
+}
+
+// CurrentStatusForProject retrieves the full record of the project's
+// current status update, or nil if the project has never had one.
+func (c *Client) CurrentStatusForProject(projectID string) (*StatusUpdate, error) {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return nil, errEmptyProjectID
+	}
+	qs := make(url.Values)
+	qs.Set("opt_fields", "current_status_update")
+	fullURL := fmt.Sprintf("%s/projects/%s?%s", baseURL, projectID, qs.Encode())
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	pcsw := new(projectCurrentStatusWrap)
+	if err := json.Unmarshal(slurp, pcsw); err != nil {
+		return nil, err
+	}
+	current := pcsw.Project.CurrentStatusUpdate
+	if current == nil {
+		return nil, nil
+	}
+	return c.FindStatusUpdateByID(fmt.Sprintf("%d", current.ID))
+}
+
+func (c *Client) DeleteStatusUpdate(statusUpdateID string) error {
+	statusUpdateID = strings.TrimSpace(statusUpdateID)
+	if statusUpdateID == "" {
+		return errEmptyStatusUpdateID
+	}
+	fullURL := fmt.Sprintf("%s/status_updates/%s", baseURL, statusUpdateID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type StatusUpdatesPage struct {
+	StatusUpdates []*StatusUpdate `json:"data"`
+	Err           error
+}
+
+type statusUpdatesPager struct {
+	StatusUpdatesPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListStatusUpdates lists the status updates of a project, portfolio or
+// goal from the most recent. If createdSince is set, only those created
+// after it are listed.
+func (c *Client) ListStatusUpdates(parentID string, createdSince *time.Time) (pagesChan chan *StatusUpdatesPage, cancelChan chan<- bool, err error) {
+	parentID = strings.TrimSpace(parentID)
+	if parentID == "" {
+		return nil, nil, errEmptyParentID
+	}
+
+	qs := make(url.Values)
+	qs.Set("parent", parentID)
+	if createdSince != nil {
+		qs.Set("created_since", createdSince.Format(time.RFC3339))
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *StatusUpdatesPage)
+
+	go c.paginate(fmt.Sprintf("/status_updates?%s", qs.Encode()), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(statusUpdatesPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.StatusUpdatesPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
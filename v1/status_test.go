@@ -0,0 +1,170 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const statusUpdateJSON = `{"data": {
+  "id": 55, "title": "Week 3", "status_type": "at_risk",
+  "html_text": "<body><strong>2 blockers</strong></body>",
+  "parent": {"id": 9, "name": "Release"}, "author": {"id": 7, "name": "Ada"}
+}}`
+
+func TestCreateStatusUpdate(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		req      *asana.StatusUpdateRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.StatusUpdateRequest{StatusType: asana.StatusUpdateOnTrack, Text: "ok"}, wantErr: true},
+		2: {req: &asana.StatusUpdateRequest{ParentID: "9", StatusType: "green", Text: "ok"}, wantErr: true},
+		3: {req: &asana.StatusUpdateRequest{ParentID: "9", StatusType: asana.StatusUpdateOnTrack}, wantErr: true},
+		4: {
+			req: &asana.StatusUpdateRequest{
+				ParentID: "9", Title: "Week 3",
+				StatusType: asana.StatusUpdateAtRisk, Text: "2 blockers",
+			},
+			wantBody: `{"data":{"parent":"9","title":"Week 3","status_type":"at_risk","text":"2 blockers"}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusCreated, body: statusUpdateJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		update, err := client.CreateStatusUpdate(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		if len(be.reqs) != 1 {
+			t.Errorf("#%d: got %d requests want 1", i, len(be.reqs))
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, "POST /api/1.0/status_updates"; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+		if update.ID != 55 || update.StatusType != asana.StatusUpdateAtRisk || update.Author == nil {
+			t.Errorf("#%d: unexpected update: %+v", i, update)
+		}
+	}
+}
+
+func TestCurrentStatusForProject(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: `{"data": {"id": 9, "current_status_update": {"id": 55, "title": "Week 3"}}}`},
+			{code: http.StatusOK, body: statusUpdateJSON},
+			{code: http.StatusOK, body: `{"data": {"id": 10, "current_status_update": null}}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	update, err := client.CurrentStatusForProject("9")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if update == nil || update.StatusType != asana.StatusUpdateAtRisk || update.HTMLText == "" {
+		t.Errorf("unexpected update: %+v", update)
+	}
+	if g, w := be.reqs[0].URL.Query().Get("opt_fields"), "current_status_update"; g != w {
+		t.Errorf("got opt_fields %q want %q", g, w)
+	}
+	if g, w := be.reqs[1].URL.Path, "/api/1.0/status_updates/55"; g != w {
+		t.Errorf("got path %q want %q", g, w)
+	}
+
+	// Projects without any status update have no current status.
+	update, err = client.CurrentStatusForProject("10")
+	if err != nil || update != nil {
+		t.Errorf("got (%+v, %v) want no update", update, err)
+	}
+	if len(be.reqs) != 3 {
+		t.Errorf("got %d requests want 3", len(be.reqs))
+	}
+
+	if _, err := client.CurrentStatusForProject(" "); err == nil {
+		t.Errorf("expected an error for an empty projectID")
+	}
+}
+
+func TestListStatusUpdates(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: `{"data": [{"id": 3}, {"id": 2}],
+			  "next_page": {"offset": "o1", "path": "/status_updates?parent=9&offset=o1"}}`},
+			{code: http.StatusOK, body: `{"data": [{"id": 1}]}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	since := time.Date(2017, time.October, 1, 0, 0, 0, 0, time.UTC)
+	pagesChan, _, err := client.ListStatusUpdates("9", &since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []int64
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			t.Fatalf("page error: %v", err)
+		}
+		for _, update := range page.StatusUpdates {
+			ids = append(ids, update.ID)
+		}
+	}
+	if len(ids) != 3 || ids[0] != 3 || ids[2] != 1 {
+		t.Errorf("got updates %v want [3 2 1]", ids)
+	}
+	query := be.reqs[0].URL.Query()
+	if query.Get("parent") != "9" || query.Get("created_since") != "2017-10-01T00:00:00Z" {
+		t.Errorf("unexpected query %q", be.reqs[0].URL.RawQuery)
+	}
+	if g, w := be.reqs[1].URL.Query().Get("offset"), "o1"; g != w {
+		t.Errorf("second page: got offset %q want %q", g, w)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Story is an entry in the activity feed of a task,
+// either a comment or a record of a change.
+type Story struct {
+	ID        int64              `json:"id,omitempty"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+	CreatedBy *NamedAndIDdEntity `json:"created_by,omitempty"`
+
+	// Type is either "comment" or "system".
+	Type            string `json:"type,omitempty"`
+	ResourceSubtype string `json:"resource_subtype,omitempty"`
+
+	Text string `json:"text,omitempty"`
+
+	// HTMLText is the rich text version of Text, see ParseRichText.
+	HTMLText string `json:"html_text,omitempty"`
+
+	IsPinned bool `json:"is_pinned,omitempty"`
+
+	Target *NamedAndIDdEntity `json:"target,omitempty"`
+}
+
+// RichText parses the story's HTMLText.
+func (s *Story) RichText() (*RichTextNode, error) {
+	return ParseRichText(s.HTMLText)
+}
+
+type CommentRequest struct {
+	TaskID string `json:"-"`
+
+	// Only one of Text and HTMLText should be set.
+	// MarkdownToHTML can be used to write HTMLText in Markdown.
+	Text     string `json:"text,omitempty"`
+	HTMLText string `json:"html_text,omitempty"`
+
+	IsPinned bool `json:"is_pinned,omitempty"`
+}
+
+var (
+	errNilCommentRequest = errors.New("expecting a non-nil commentRequest")
+	errEmptyComment      = errors.New("expecting either text or htmlText to be set")
+)
+
+func (cr *CommentRequest) Validate() error {
+	if cr == nil {
+		return errNilCommentRequest
+	}
+	if strings.TrimSpace(cr.TaskID) == "" {
+		return errEmptyTaskID
+	}
+	if strings.TrimSpace(cr.Text) == "" && strings.TrimSpace(cr.HTMLText) == "" {
+		return errEmptyComment
+	}
+	return nil
+}
+
+type storyWrap struct {
+	Story *Story `json:"data"`
+}
+
+func (c *Client) CommentOnTask(cr *CommentRequest) (*Story, error) {
+	if err := cr.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/tasks/%s/stories", strings.TrimSpace(cr.TaskID))
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, cr)
+	if err != nil {
+		return nil, err
+	}
+	sw := new(storyWrap)
+	if err := json.Unmarshal(slurp, sw); err != nil {
+		return nil, err
+	}
+	return sw.Story, nil
+}
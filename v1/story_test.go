@@ -0,0 +1,122 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestCommentOnTask(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		req      *asana.CommentRequest
+		wantErr  bool
+		wantBody string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.CommentRequest{Text: "no task"}, wantErr: true},
+		2: {req: &asana.CommentRequest{TaskID: "1001", Text: "  "}, wantErr: true},
+		3: {
+			req:      &asana.CommentRequest{TaskID: " 1001 ", Text: "LGTM"},
+			wantBody: `{"data":{"text":"LGTM"}}`,
+		},
+		4: {
+			req: &asana.CommentRequest{
+				TaskID:   "1001",
+				HTMLText: asana.MarkdownToHTML("Ship **it**"),
+				IsPinned: true,
+			},
+			// encoding/json escapes the HTML's angle brackets.
+			wantBody: `{"data":{"html_text":"\u003cbody\u003eShip \u003cstrong\u003eit\u003c/strong\u003e\u003c/body\u003e","is_pinned":true}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{
+			resps: []scriptedResp{{code: http.StatusCreated, body: `{"data": {
+			  "id": 77, "type": "comment", "resource_subtype": "comment_added",
+			  "html_text": "<body>Ship <strong>it</strong></body>",
+			  "created_by": {"id": 7, "name": "Ada"}, "target": {"id": 1001, "name": "Release"}
+			}}`}},
+		}
+		client.SetHTTPRoundTripper(be)
+
+		story, err := client.CommentOnTask(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			if len(be.reqs) != 0 {
+				t.Errorf("#%d: unexpectedly made %d requests", i, len(be.reqs))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+
+		if len(be.reqs) != 1 {
+			t.Errorf("#%d: got %d requests want 1", i, len(be.reqs))
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method, "POST"; g != w {
+			t.Errorf("#%d: got method %q want %q", i, g, w)
+		}
+		if g, w := req.URL.Path, "/api/1.0/tasks/1001/stories"; g != w {
+			t.Errorf("#%d: got path %q want %q", i, g, w)
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+
+		if story.ID != 77 || story.Target == nil || story.Target.ID != 1001 {
+			t.Errorf("#%d: unexpected story: %+v", i, story)
+		}
+		rtn, err := story.RichText()
+		if err != nil {
+			t.Errorf("#%d: parsing the story's text: %v", i, err)
+		} else if g, w := rtn.Markdown(), "Ship **it**"; g != w {
+			t.Errorf("#%d: got markdown %q want %q", i, g, w)
+		}
+	}
+}
+
+func TestStoryRichText(t *testing.T) {
+	story := new(asana.Story)
+	blob := `{"id": 1, "type": "system", "text": "Ada completed this task"}`
+	if err := json.Unmarshal([]byte(blob), story); err != nil {
+		t.Fatalf("unmarshaling: %v", err)
+	}
+	// Stories without rich text still parse, to nothing.
+	rtn, err := story.RichText()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g := rtn.PlainText(); g != "" {
+		t.Errorf("got text %q want none", g)
+	}
+}
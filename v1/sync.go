@@ -0,0 +1,238 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CheckpointStore persists the high-water marks of incremental syncs.
+// LoadCheckpoint must return the zero time.Time, and no error,
+// for a key that has never been saved.
+type CheckpointStore interface {
+	LoadCheckpoint(key string) (time.Time, error)
+	SaveCheckpoint(key string, checkpoint time.Time) error
+}
+
+type memoryCheckpointStore struct {
+	sync.RWMutex
+	checkpoints map[string]time.Time
+}
+
+var _ CheckpointStore = (*memoryCheckpointStore)(nil)
+
+// NewMemoryCheckpointStore returns a CheckpointStore that only
+// lives as long as the process, useful for long running syncers.
+func NewMemoryCheckpointStore() CheckpointStore {
+	return &memoryCheckpointStore{checkpoints: make(map[string]time.Time)}
+}
+
+func (mcs *memoryCheckpointStore) LoadCheckpoint(key string) (time.Time, error) {
+	mcs.RLock()
+	defer mcs.RUnlock()
+	return mcs.checkpoints[key], nil
+}
+
+func (mcs *memoryCheckpointStore) SaveCheckpoint(key string, checkpoint time.Time) error {
+	mcs.Lock()
+	defer mcs.Unlock()
+	mcs.checkpoints[key] = checkpoint
+	return nil
+}
+
+type fileCheckpointStore struct {
+	sync.Mutex
+	path string
+}
+
+var _ CheckpointStore = (*fileCheckpointStore)(nil)
+
+// NewFileCheckpointStore returns a CheckpointStore that keeps
+// every checkpoint as JSON in the file at path, so that they
+// survive between runs of a sync job.
+func NewFileCheckpointStore(path string) CheckpointStore {
+	return &fileCheckpointStore{path: path}
+}
+
+func (fcs *fileCheckpointStore) readAll() (map[string]time.Time, error) {
+	checkpoints := make(map[string]time.Time)
+	blob, err := ioutil.ReadFile(fcs.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return checkpoints, nil
+		}
+		return nil, err
+	}
+	if len(blob) == 0 {
+		return checkpoints, nil
+	}
+	if err := json.Unmarshal(blob, &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+func (fcs *fileCheckpointStore) LoadCheckpoint(key string) (time.Time, error) {
+	fcs.Lock()
+	defer fcs.Unlock()
+
+	checkpoints, err := fcs.readAll()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return checkpoints[key], nil
+}
+
+func (fcs *fileCheckpointStore) SaveCheckpoint(key string, checkpoint time.Time) error {
+	fcs.Lock()
+	defer fcs.Unlock()
+
+	checkpoints, err := fcs.readAll()
+	if err != nil {
+		return err
+	}
+	checkpoints[key] = checkpoint
+	blob, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(fcs.path, blob)
+}
+
+// writeFileAtomically writes to a temporary file first and then renames
+// it over path, so that a crash midway can't leave a truncated file behind.
+func writeFileAtomically(path string, blob []byte) error {
+	tmpPath := fmt.Sprintf("%s.tmp", path)
+	if err := ioutil.WriteFile(tmpPath, blob, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+type TaskSync struct {
+	ProjectID string
+
+	Store CheckpointStore
+
+	// Overlap is subtracted from the stored checkpoint to account
+	// for clock skew between this machine and Asana's servers.
+	// Tasks changed within the overlap are returned again.
+	Overlap time.Duration
+
+	// Limit is the page size used while listing tasks.
+	Limit int
+}
+
+type TaskSyncResult struct {
+	// Tasks are those changed since the previous run
+	// or all the tasks of the project on the first run.
+	Tasks []*Task
+
+	// Since is the checkpoint that Tasks were listed from;
+	// it is the zero time.Time on the first run.
+	Since time.Time
+
+	// Checkpoint is the high-water mark saved for the next run.
+	Checkpoint time.Time
+}
+
+var (
+	errNilTaskSync        = errors.New("expecting a non-nil taskSync")
+	errNilCheckpointStore = errors.New("expecting a non-nil checkpoint store")
+)
+
+func (ts *TaskSync) Validate() error {
+	if ts == nil {
+		return errNilTaskSync
+	}
+	if strings.TrimSpace(ts.ProjectID) == "" {
+		return errEmptyProjectID
+	}
+	if ts.Store == nil {
+		return errNilCheckpointStore
+	}
+	return nil
+}
+
+func (ts *TaskSync) checkpointKey() string {
+	return fmt.Sprintf("project:%s", strings.TrimSpace(ts.ProjectID))
+}
+
+// SyncTasksForProject returns the tasks of a project that changed since
+// the checkpoint saved by the previous run and then advances that
+// checkpoint. The checkpoint is only saved once every page has been
+// retrieved, so a failed run is simply retried from the same point.
+func (c *Client) SyncTasksForProject(ts *TaskSync) (*TaskSyncResult, error) {
+	if err := ts.Validate(); err != nil {
+		return nil, err
+	}
+
+	key := ts.checkpointKey()
+	since, err := ts.Store.LoadCheckpoint(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// The high-water mark is taken before listing so that
+	// changes made while we page through are picked up next time.
+	runStart := time.Now().UTC()
+
+	qs := make(url.Values)
+	qs.Set("project", strings.TrimSpace(ts.ProjectID))
+	limit := ts.Limit
+	if limit <= 0 {
+		limit = defaultTaskLimit
+	}
+	qs.Set("limit", strconv.Itoa(limit))
+	if !since.IsZero() {
+		modifiedSince := since.Add(-ts.Overlap).UTC()
+		qs.Set("modified_since", modifiedSince.Format(time.RFC3339))
+	}
+
+	pagesChan, _, err := c.doTasksPaging(fmt.Sprintf("/tasks?%s", qs.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TaskSyncResult{Since: since, Checkpoint: runStart}
+	var pageErr error
+	for page := range pagesChan {
+		if page.Err != nil {
+			if pageErr == nil {
+				pageErr = page.Err
+			}
+			continue
+		}
+		result.Tasks = append(result.Tasks, page.Tasks...)
+	}
+	if pageErr != nil {
+		return nil, pageErr
+	}
+
+	if err := ts.Store.SaveCheckpoint(key, runStart); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestSyncTasksForProject(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	be := &cannedBackend{body: `{"data": [{"id": 1, "name": "a"}]}`}
+	client.SetHTTPRoundTripper(be)
+
+	dir, err := ioutil.TempDir("", "asana-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := asana.NewFileCheckpointStore(filepath.Join(dir, "checkpoints.json"))
+	ts := &asana.TaskSync{ProjectID: "42", Store: store, Overlap: time.Minute}
+
+	// The first run has no checkpoint so must list everything.
+	first, err := client.SyncTasksForProject(ts)
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if !first.Since.IsZero() {
+		t.Errorf("first sync: got since %v want the zero time", first.Since)
+	}
+	if got := be.reqs[0].URL.Query().Get("modified_since"); got != "" {
+		t.Errorf("first sync: unexpectedly sent modified_since=%q", got)
+	}
+
+	// The second run must resume from the saved checkpoint, less the overlap.
+	second, err := client.SyncTasksForProject(ts)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if !second.Since.Equal(first.Checkpoint) {
+		t.Errorf("second sync: got since %v want %v", second.Since, first.Checkpoint)
+	}
+	wantSince := first.Checkpoint.Add(-time.Minute).Format(time.RFC3339)
+	query := be.reqs[1].URL.Query()
+	if got := query.Get("modified_since"); got != wantSince {
+		t.Errorf("second sync: got modified_since=%q want %q", got, wantSince)
+	}
+	if got := query.Get("project"); got != "42" {
+		t.Errorf("second sync: got project=%q want \"42\"", got)
+	}
+	if len(second.Tasks) != 1 {
+		t.Errorf("second sync: got %d tasks want 1", len(second.Tasks))
+	}
+}
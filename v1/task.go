@@ -15,14 +15,16 @@
 package asana
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/orijtech/otils"
@@ -37,10 +39,16 @@ type Task struct {
 
 	AssigneeStatus AssigneeStatus `json:"assignee_status,omitempty"`
 
-	CustomFields []CustomField `json:"custom_fields,omitempty"`
+	// AssigneeSection is the section of the assignee's task list
+	// that the task is in, which supersedes AssigneeStatus.
+	AssigneeSection *NamedAndIDdEntity `json:"assignee_section,omitempty"`
 
-	DueOn *YYYYMMDD  `json:"due_on,omitempty"`
-	DueAt *time.Time `json:"due_at,omitempty"`
+	CustomFields []*CustomField `json:"custom_fields,omitempty"`
+
+	// StartOn can only be set along with DueOn, see DateRange.
+	StartOn *Date      `json:"start_on,omitempty"`
+	DueOn   *Date      `json:"due_on,omitempty"`
+	DueAt   *time.Time `json:"due_at,omitempty"`
 
 	Metadata Metadata `json:"external,omitempty"`
 
@@ -55,6 +63,9 @@ type Task struct {
 
 	Notes string `json:"notes,omitempty"`
 
+	// HTMLNotes is the rich text version of Notes, see ParseRichText.
+	HTMLNotes string `json:"html_notes,omitempty"`
+
 	Projects   []*Project `json:"projects,omitempty"`
 	ParentTask *Task      `json:"parent,omitempty"`
 
@@ -63,6 +74,10 @@ type Task struct {
 	Memberships []*Membership `json:"memberships,omitempty"`
 
 	Tags []*NamedAndIDdEntity `json:"tags,omitempty"`
+
+	// ActualTimeMinutes is the total time logged against
+	// the task, see ListTimeTrackingEntriesForTask.
+	ActualTimeMinutes float64 `json:"actual_time_minutes,omitempty"`
 }
 
 type NamedAndIDdEntity struct {
@@ -100,69 +115,8 @@ func (as AssigneeStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(string(as))
 }
 
-type CustomField map[string]interface{}
-
 type Metadata map[string]interface{}
 
-type YYYYMMDD struct {
-	sync.RWMutex
-
-	YYYY int64
-	MM   int64
-	DD   int64
-
-	str string
-}
-
-var _ json.Marshaler = (*YYYYMMDD)(nil)
-var _ json.Unmarshaler = (*YYYYMMDD)(nil)
-
-func (ymd *YYYYMMDD) UnmarshalJSON(b []byte) error {
-	// Format of data is: 2012-03-26
-	unquoted, err := strconv.Unquote(string(b))
-	if err != nil {
-		return err
-	}
-	splits := strings.Split(unquoted, "-")
-	if len(splits) < 3 {
-		return errors.New("expecting YYYY-MM-DD")
-	}
-
-	var intified []int64
-	for _, split := range splits {
-		it, err := intifyIt(split)
-		if err != nil {
-			return err
-		}
-		intified = append(intified, it)
-	}
-
-	ymd.YYYY = intified[0]
-	ymd.MM = intified[1]
-	ymd.DD = intified[2]
-	return nil
-}
-
-func (ymd *YYYYMMDD) MarshalJSON() ([]byte, error) {
-	return json.Marshal(string(ymd.String()))
-}
-
-func (ymd *YYYYMMDD) String() string {
-	if ymd == nil {
-		return ""
-	}
-	ymd.Lock()
-	defer ymd.Unlock()
-	if ymd.str == "" {
-		ymd.str = fmt.Sprintf("%d-%d-%d", ymd.YYYY, ymd.MM, ymd.DD)
-	}
-	return ymd.str
-}
-
-func intifyIt(st string) (int64, error) {
-	return strconv.ParseInt(st, 10, 64)
-}
-
 func (c *Client) doAuthReqThenSlurpBody(req *http.Request) ([]byte, http.Header, error) {
 	req.Header.Set("Authorization", c.personalAccessTokenAuthValue())
 	res, err := c.httpClient().Do(req)
@@ -188,6 +142,27 @@ func (c *Client) doAuthReqThenSlurpBody(req *http.Request) ([]byte, http.Header,
 	return slurp, res.Header, err
 }
 
+type dataWrap struct {
+	Data interface{} `json:"data"`
+}
+
+// doJSONReqThenSlurpBody is for endpoints whose bodies can't be
+// expressed as url-encoded forms e.g. those taking nested objects.
+// It wraps data in the {"data": ...} envelope that the API expects.
+func (c *Client) doJSONReqThenSlurpBody(method, path string, data interface{}) ([]byte, http.Header, error) {
+	blob, err := json.Marshal(&dataWrap{Data: data})
+	if err != nil {
+		return nil, nil, err
+	}
+	fullURL := fmt.Sprintf("%s%s", baseURL, path)
+	req, err := http.NewRequest(method, fullURL, bytes.NewReader(blob))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.doAuthReqThenSlurpBody(req)
+}
+
 var readOnlyFields = []string{
 	"num_hearts",
 }
@@ -197,6 +172,14 @@ type taskResultWrap struct {
 }
 
 func (c *Client) CreateTask(t *TaskRequest) (*Task, error) {
+	if t != nil {
+		// Asana accepts StartOn along with either DueOn or DueAt.
+		dates := DateRange{Start: dateOrZero(t.StartOn), Due: dueDate(t.DueOn, t.DueAt)}
+		if err := dates.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	// This endpoint takes in url-encoded data
 	qs, err := otils.ToURLValues(t)
 	if err != nil {
@@ -206,6 +189,12 @@ func (c *Client) CreateTask(t *TaskRequest) (*Task, error) {
 	for _, field := range readOnlyFields {
 		qs.Del(field)
 	}
+	if t != nil {
+		for _, cf := range t.CustomFields {
+			key := fmt.Sprintf("custom_fields[%d]", cf.ID)
+			qs.Set(key, cf.formValue())
+		}
+	}
 
 	fullURL := fmt.Sprintf("%s/tasks", baseURL)
 	queryStr := qs.Encode()
@@ -252,12 +241,23 @@ type TaskRequest struct {
 	Completed   bool       `json:"completed,omitempty"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 
+	// ModifiedSince and CompletedSince only apply when listing tasks.
+	// ModifiedSince restricts results to tasks changed after it while
+	// CompletedSince returns only tasks that are incomplete or that
+	// were completed after it.
+	ModifiedSince  *time.Time `json:"modified_since,omitempty"`
+	CompletedSince *time.Time `json:"completed_since,omitempty"`
+
 	AssigneeStatus AssigneeStatus `json:"assignee_status,omitempty"`
 
-	CustomFields []CustomField `json:"custom_fields,omitempty"`
+	// CustomFields are sent as their values keyed by
+	// custom field ID; see CustomField.SetNumber and friends.
+	CustomFields []*CustomField `json:"-"`
 
-	DueOn *YYYYMMDD  `json:"due_on,omitempty"`
-	DueAt *time.Time `json:"due_at,omitempty"`
+	// StartOn can only be set along with DueOn, see DateRange.
+	StartOn *Date      `json:"start_on,omitempty"`
+	DueOn   *Date      `json:"due_on,omitempty"`
+	DueAt   *time.Time `json:"due_at,omitempty"`
 
 	Metadata Metadata `json:"external,omitempty"`
 
@@ -272,6 +272,10 @@ type TaskRequest struct {
 
 	Notes string `json:"notes,omitempty"`
 
+	// HTMLNotes takes precedence over Notes if both are set.
+	// MarkdownToHTML can be used to write it in Markdown.
+	HTMLNotes string `json:"html_notes,omitempty"`
+
 	Projects   []*NamedAndIDdEntity `json:"projects,omitempty"`
 	ParentTask *Task                `json:"parent,omitempty"`
 
@@ -370,6 +374,10 @@ func (c *Client) ListMyWorkspaces() (chan *WorkspacePage, error) {
 var errEmptyTaskID = errors.New("expecting a non-empty taskID")
 
 func (c *Client) FindTaskByID(taskID string) (*Task, error) {
+	return c.findTaskByID(context.Background(), taskID)
+}
+
+func (c *Client) findTaskByID(ctx context.Context, taskID string) (*Task, error) {
 	taskID = strings.TrimSpace(taskID)
 	if taskID == "" {
 		return nil, errEmptyTaskID
@@ -379,6 +387,7 @@ func (c *Client) FindTaskByID(taskID string) (*Task, error) {
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 	slurp, _, err := c.doAuthReqThenSlurpBody(req)
 	if err != nil {
 		return nil, err
@@ -389,10 +398,87 @@ func (c *Client) FindTaskByID(taskID string) (*Task, error) {
 var errEmptyProjectID = errors.New("expecting a non-empty projectID")
 
 func (c *Client) ListTasksForProject(treq *TaskRequest) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
+	if treq == nil || strings.TrimSpace(treq.ProjectID) == "" {
+		return nil, nil, errEmptyProjectID
+	}
 	path := fmt.Sprintf("/projects/%s/tasks", treq.ProjectID)
+	if qs := treq.listingValues(); len(qs) > 0 {
+		path = fmt.Sprintf("%s?%s", path, qs.Encode())
+	}
 	return c.doTasksPaging(path)
 }
 
+// listingValues returns the query parameters that
+// narrow down or paginate a listing of tasks.
+func (treq *TaskRequest) listingValues() url.Values {
+	qs := make(url.Values)
+	if treq.Limit > 0 {
+		qs.Set("limit", strconv.Itoa(treq.Limit))
+	}
+	if treq.ModifiedSince != nil {
+		qs.Set("modified_since", treq.ModifiedSince.UTC().Format(time.RFC3339))
+	}
+	if treq.CompletedSince != nil {
+		qs.Set("completed_since", treq.CompletedSince.UTC().Format(time.RFC3339))
+	}
+	return qs
+}
+
+// ListSubtasks lists the direct subtasks of a task.
+func (c *Client) ListSubtasks(taskID string) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, nil, errEmptyTaskID
+	}
+	return c.doTasksPaging(fmt.Sprintf("/tasks/%s/subtasks", taskID))
+}
+
+// walkProjectTasks invokes fn with each task of the project, each task
+// followed by its subtasks at every depth, stopping at the first error
+// that fn or the listings encounter or once ctx is done.
+func (c *Client) walkProjectTasks(ctx context.Context, projectID string, fn func(*Task) error) error {
+	tasksChan, cancel, err := c.TasksForProject(projectID)
+	if err != nil {
+		return err
+	}
+	return c.walkTasks(ctx, tasksChan, cancel, fn)
+}
+
+func (c *Client) walkTasks(ctx context.Context, tasksChan chan *TaskResultPage, cancel chan<- bool, fn func(*Task) error) error {
+	defer func() {
+		cancel <- true
+		// The tasks pager doesn't heed cancellation so
+		// drain it to let it finish should we return early.
+		go func() {
+			for range tasksChan {
+			}
+		}()
+	}()
+
+	for page := range tasksChan {
+		if err := page.Err; err != nil {
+			return err
+		}
+		for _, task := range page.Tasks {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(task); err != nil {
+				return err
+			}
+
+			subtasksChan, cancelSubtasks, err := c.ListSubtasks(fmt.Sprintf("%d", task.ID))
+			if err != nil {
+				return err
+			}
+			if err := c.walkTasks(ctx, subtasksChan, cancelSubtasks, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (c *Client) doTasksPaging(path string) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
 	tasksPageChan := make(chan *TaskResultPage)
 	cancelChan = make(chan bool, 1)
@@ -422,7 +508,7 @@ func (c *Client) doTasksPaging(path string) (resultsChan chan *TaskResultPage, c
 			taskPage := pager.TaskResultPage
 			tasksPageChan <- &taskPage
 
-			if np := pager.NextPage; np != nil && np.Path == "" {
+			if np := pager.NextPage; np != nil && np.Path != "" {
 				path = np.Path
 			} else {
 				// End of this pagination
@@ -444,3 +530,96 @@ func (c *Client) DeleteTask(taskID string) error {
 	_, _, err := c.doAuthReqThenSlurpBody(req)
 	return err
 }
+
+// TaskDuplicateOption is something of a task to copy over besides its name.
+type TaskDuplicateOption string
+
+const (
+	TaskDuplicateAssignee     TaskDuplicateOption = "assignee"
+	TaskDuplicateAttachments  TaskDuplicateOption = "attachments"
+	TaskDuplicateDates        TaskDuplicateOption = "dates"
+	TaskDuplicateDependencies TaskDuplicateOption = "dependencies"
+	TaskDuplicateFollowers    TaskDuplicateOption = "followers"
+	TaskDuplicateNotes        TaskDuplicateOption = "notes"
+	TaskDuplicateParent       TaskDuplicateOption = "parent"
+	TaskDuplicateProjects     TaskDuplicateOption = "projects"
+	TaskDuplicateSubtasks     TaskDuplicateOption = "subtasks"
+	TaskDuplicateTags         TaskDuplicateOption = "tags"
+)
+
+// TaskDuplicateRequest describes the copy of the
+// task identified by TaskID that DuplicateTask makes.
+type TaskDuplicateRequest struct {
+	TaskID string `json:"-"`
+
+	// Name is that of the new task.
+	Name string `json:"name"`
+
+	Include []TaskDuplicateOption `json:"-"`
+
+	// WaitOptions configures the polling of the duplication job.
+	WaitOptions *JobWaitOptions `json:"-"`
+}
+
+type taskDuplicateBody struct {
+	*TaskDuplicateRequest
+
+	// Include is sent as a comma separated list.
+	Include string `json:"include,omitempty"`
+}
+
+var (
+	errNilTaskDuplicateRequest = errors.New("expecting a non-nil taskDuplicateRequest")
+	errNoNewTask               = errors.New("the job didn't create a task")
+)
+
+// Validate checks that the task to copy and the name of the copy are set.
+func (tdr *TaskDuplicateRequest) Validate() error {
+	if tdr == nil {
+		return errNilTaskDuplicateRequest
+	}
+	if strings.TrimSpace(tdr.TaskID) == "" {
+		return errEmptyTaskID
+	}
+	if strings.TrimSpace(tdr.Name) == "" {
+		return errEmptyDuplicateName
+	}
+	return nil
+}
+
+// DuplicateTask copies a task along with the parts of it in Include.
+// Asana makes the copy asynchronously so DuplicateTask waits for the
+// job to complete, or for ctx to be done, and then returns the new task.
+func (c *Client) DuplicateTask(ctx context.Context, tdr *TaskDuplicateRequest) (*Task, error) {
+	if err := tdr.Validate(); err != nil {
+		return nil, err
+	}
+
+	var include []string
+	for _, opt := range tdr.Include {
+		include = append(include, string(opt))
+	}
+	body := &taskDuplicateBody{TaskDuplicateRequest: tdr, Include: strings.Join(include, ",")}
+
+	path := fmt.Sprintf("/tasks/%s/duplicate", strings.TrimSpace(tdr.TaskID))
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, body)
+	if err != nil {
+		return nil, err
+	}
+	job, err := parseOutJobFromData(slurp)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, errEmptyJobID
+	}
+
+	result, err := c.WaitForJob(ctx, fmt.Sprintf("%d", job.ID), tdr.WaitOptions)
+	if err != nil {
+		return nil, err
+	}
+	if result.Task == nil {
+		return nil, errNoNewTask
+	}
+	return result.Task, nil
+}
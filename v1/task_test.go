@@ -0,0 +1,116 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestDuplicateTask(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	waitOpts := &asana.JobWaitOptions{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+
+	tests := [...]struct {
+		req       *asana.TaskDuplicateRequest
+		resps     []scriptedResp
+		wantErr   bool
+		wantBody  string
+		wantPaths []string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.TaskDuplicateRequest{Name: "Checklist copy"}, wantErr: true},
+		2: {req: &asana.TaskDuplicateRequest{TaskID: "12", Name: " "}, wantErr: true},
+		3: {
+			req: &asana.TaskDuplicateRequest{
+				TaskID: " 12 ", Name: "Checklist copy", WaitOptions: waitOpts,
+				Include: []asana.TaskDuplicateOption{asana.TaskDuplicateNotes, asana.TaskDuplicateSubtasks, asana.TaskDuplicateDates},
+			},
+			resps: []scriptedResp{
+				{code: http.StatusCreated, body: `{"data": {"id": 9, "status": "not_started", "new_task": {"id": 13}}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "in_progress", "new_task": {"id": 13}}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "succeeded", "new_task": {"id": 13}}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 13, "name": "Checklist copy"}}`},
+			},
+			wantBody: `{"data":{"name":"Checklist copy","include":"notes,subtasks,dates"}}`,
+			wantPaths: []string{
+				"POST /api/1.0/tasks/12/duplicate",
+				"GET /api/1.0/jobs/9", "GET /api/1.0/jobs/9",
+				"GET /api/1.0/tasks/13",
+			},
+		},
+		4: {
+			// Nothing to include is left out of the body.
+			req: &asana.TaskDuplicateRequest{TaskID: "12", Name: "Bare copy", WaitOptions: waitOpts},
+			resps: []scriptedResp{
+				{code: http.StatusCreated, body: `{"data": {"id": 9, "status": "not_started"}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "succeeded", "new_task": {"id": 13}}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 13, "name": "Bare copy"}}`},
+			},
+			wantBody:  `{"data":{"name":"Bare copy"}}`,
+			wantPaths: []string{"POST /api/1.0/tasks/12/duplicate", "GET /api/1.0/jobs/9", "GET /api/1.0/tasks/13"},
+		},
+		5: {
+			req: &asana.TaskDuplicateRequest{TaskID: "12", Name: "Doomed copy", WaitOptions: waitOpts},
+			resps: []scriptedResp{
+				{code: http.StatusCreated, body: `{"data": {"id": 9, "status": "not_started"}}`},
+				{code: http.StatusOK, body: `{"data": {"id": 9, "status": "failed"}}`},
+			},
+			wantErr: true,
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: tt.resps}
+		client.SetHTTPRoundTripper(be)
+
+		task, err := client.DuplicateTask(context.Background(), tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		if task == nil || task.ID != 13 || task.Name != tt.req.Name {
+			t.Errorf("#%d: unexpected task: %+v", i, task)
+		}
+
+		if g, w := len(be.reqs), len(tt.wantPaths); g != w {
+			t.Errorf("#%d: got %d requests want %d", i, g, w)
+			continue
+		}
+		for j, req := range be.reqs {
+			if g, w := req.Method+" "+req.URL.Path, tt.wantPaths[j]; g != w {
+				t.Errorf("#%d: request #%d: got %q want %q", i, j, g, w)
+			}
+		}
+		blob, _ := ioutil.ReadAll(be.reqs[0].Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+	}
+}
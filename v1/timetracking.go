@@ -0,0 +1,388 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeTrackingEntry is time logged against a task.
+type TimeTrackingEntry struct {
+	ID              int64 `json:"id,omitempty"`
+	DurationMinutes int   `json:"duration_minutes,omitempty"`
+
+	// EnteredOn is the day that the time was logged for.
+	EnteredOn *Date `json:"entered_on,omitempty"`
+
+	CreatedBy *NamedAndIDdEntity `json:"created_by,omitempty"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+
+	Task *NamedAndIDdEntity `json:"task,omitempty"`
+}
+
+type TimeTrackingEntryRequest struct {
+	// TaskID is only used when creating an entry.
+	TaskID string `json:"-"`
+
+	// EntryID is only used when updating an entry.
+	EntryID string `json:"-"`
+
+	DurationMinutes int `json:"duration_minutes,omitempty"`
+
+	// EnteredOn defaults to today when creating an entry.
+	EnteredOn *Date `json:"entered_on,omitempty"`
+}
+
+var (
+	errNilTimeTrackingEntryRequest = errors.New("expecting a non-nil timeTrackingEntryRequest")
+	errEmptyTimeTrackingEntryID    = errors.New("expecting a non-empty timeTrackingEntryID")
+	errNonPositiveDuration         = errors.New("expecting a positive durationMinutes")
+	errNegativeDuration            = errors.New("expecting a non-negative durationMinutes")
+)
+
+type timeTrackingEntryWrap struct {
+	TimeTrackingEntry *TimeTrackingEntry `json:"data"`
+}
+
+func parseOutTimeTrackingEntryFromData(blob []byte) (*TimeTrackingEntry, error) {
+	ttew := new(timeTrackingEntryWrap)
+	if err := json.Unmarshal(blob, ttew); err != nil {
+		return nil, err
+	}
+	return ttew.TimeTrackingEntry, nil
+}
+
+func (c *Client) CreateTimeTrackingEntry(tter *TimeTrackingEntryRequest) (*TimeTrackingEntry, error) {
+	if tter == nil {
+		return nil, errNilTimeTrackingEntryRequest
+	}
+	taskID := strings.TrimSpace(tter.TaskID)
+	if taskID == "" {
+		return nil, errEmptyTaskID
+	}
+	if tter.DurationMinutes <= 0 {
+		return nil, errNonPositiveDuration
+	}
+	path := fmt.Sprintf("/tasks/%s/time_tracking_entries", taskID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", path, tter)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTimeTrackingEntryFromData(slurp)
+}
+
+func (c *Client) FindTimeTrackingEntryByID(timeTrackingEntryID string) (*TimeTrackingEntry, error) {
+	timeTrackingEntryID = strings.TrimSpace(timeTrackingEntryID)
+	if timeTrackingEntryID == "" {
+		return nil, errEmptyTimeTrackingEntryID
+	}
+	fullURL := fmt.Sprintf("%s/time_tracking_entries/%s", baseURL, timeTrackingEntryID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTimeTrackingEntryFromData(slurp)
+}
+
+// UpdateTimeTrackingEntry changes the duration or date of an entry, a
+// DurationMinutes of 0 leaving the duration unchanged. An entry cannot
+// be moved to another task.
+func (c *Client) UpdateTimeTrackingEntry(tter *TimeTrackingEntryRequest) (*TimeTrackingEntry, error) {
+	if tter == nil {
+		return nil, errNilTimeTrackingEntryRequest
+	}
+	entryID := strings.TrimSpace(tter.EntryID)
+	if entryID == "" {
+		return nil, errEmptyTimeTrackingEntryID
+	}
+	if tter.DurationMinutes < 0 {
+		return nil, errNegativeDuration
+	}
+	path := fmt.Sprintf("/time_tracking_entries/%s", entryID)
+	slurp, _, err := c.doJSONReqThenSlurpBody("PUT", path, tter)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTimeTrackingEntryFromData(slurp)
+}
+
+func (c *Client) DeleteTimeTrackingEntry(timeTrackingEntryID string) error {
+	timeTrackingEntryID = strings.TrimSpace(timeTrackingEntryID)
+	if timeTrackingEntryID == "" {
+		return errEmptyTimeTrackingEntryID
+	}
+	fullURL := fmt.Sprintf("%s/time_tracking_entries/%s", baseURL, timeTrackingEntryID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type TimeTrackingEntriesPage struct {
+	TimeTrackingEntries []*TimeTrackingEntry `json:"data"`
+	Err                 error
+}
+
+type timeTrackingEntriesPager struct {
+	TimeTrackingEntriesPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListTimeTrackingEntriesForTask(taskID string) (pagesChan chan *TimeTrackingEntriesPage, cancelChan chan<- bool, err error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, nil, errEmptyTaskID
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *TimeTrackingEntriesPage)
+
+	go c.paginate(fmt.Sprintf("/tasks/%s/time_tracking_entries", taskID), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(timeTrackingEntriesPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.TimeTrackingEntriesPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
+
+// UserTime is the time that a user logged.
+type UserTime struct {
+	User    *NamedAndIDdEntity `json:"user"`
+	Minutes int                `json:"minutes"`
+	Entries int                `json:"entries"`
+}
+
+// TimeReport totals the time logged over a date range.
+type TimeReport struct {
+	Range DateRange `json:"-"`
+
+	TotalMinutes int `json:"total_minutes"`
+
+	// Users is sorted by the most time logged first.
+	Users []*UserTime `json:"users,omitempty"`
+
+	// TaskMinutes is the time logged per task ID.
+	TaskMinutes map[int64]int `json:"task_minutes,omitempty"`
+
+	// ProjectMinutes is the time logged per project ID, as reported
+	// by TimeReportForProject for each of the projects requested.
+	ProjectMinutes map[string]int `json:"project_minutes,omitempty"`
+}
+
+// AggregateTimeTrackingEntries totals the entries that were entered
+// on days in dr, or all entries if dr is the zero DateRange.
+func AggregateTimeTrackingEntries(entries []*TimeTrackingEntry, dr DateRange) *TimeReport {
+	report := &TimeReport{Range: dr, TaskMinutes: make(map[int64]int)}
+	byUser := make(map[int64]*UserTime)
+	for _, entry := range entries {
+		if entry == nil {
+			continue
+		}
+		if !loggedIn(dr, entry) {
+			continue
+		}
+
+		report.TotalMinutes += entry.DurationMinutes
+		if entry.Task != nil {
+			report.TaskMinutes[entry.Task.ID] += entry.DurationMinutes
+		}
+
+		user := entry.CreatedBy
+		if user == nil {
+			user = new(NamedAndIDdEntity)
+		}
+		ut, ok := byUser[user.ID]
+		if !ok {
+			ut = &UserTime{User: user}
+			byUser[user.ID] = ut
+			report.Users = append(report.Users, ut)
+		}
+		ut.Minutes += entry.DurationMinutes
+		ut.Entries++
+	}
+
+	sort.SliceStable(report.Users, func(i, j int) bool {
+		ui, uj := report.Users[i], report.Users[j]
+		if ui.Minutes != uj.Minutes {
+			return ui.Minutes > uj.Minutes
+		}
+		return ui.User.Name < uj.User.Name
+	})
+	return report
+}
+
+// loggedIn reports whether the entry was entered on a day
+// in dr, which is always the case for the zero DateRange.
+func loggedIn(dr DateRange, entry *TimeTrackingEntry) bool {
+	return dr == DateRange{} || dr.Contains(dateOrZero(entry.EnteredOn))
+}
+
+type TimeReportRequest struct {
+	ProjectID string `json:"project"`
+
+	// ProjectIDs are more projects to report on along with
+	// ProjectID, e.g. all the projects billed to a customer.
+	ProjectIDs []string `json:"-"`
+
+	// UserID if set restricts the report to
+	// time that was logged by that user.
+	UserID UserID `json:"user,omitempty"`
+
+	// Range is the span of days to report on,
+	// the zero DateRange meaning all time.
+	Range DateRange `json:"-"`
+}
+
+var (
+	errNilTimeReportRequest = errors.New("expecting a non-nil timeReportRequest")
+	errNonNumericUserID     = errors.New("expecting a numeric userID")
+)
+
+func (trr *TimeReportRequest) Validate() error {
+	if trr == nil {
+		return errNilTimeReportRequest
+	}
+	if len(trr.projectIDs()) == 0 {
+		return errEmptyProjectID
+	}
+	if userID := strings.TrimSpace(string(trr.UserID)); userID != "" {
+		if _, err := strconv.ParseInt(userID, 10, 64); err != nil {
+			return errNonNumericUserID
+		}
+	}
+	return nil
+}
+
+// projectIDs returns the distinct non-blank IDs of ProjectID and ProjectIDs.
+func (trr *TimeReportRequest) projectIDs() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, projectID := range append([]string{trr.ProjectID}, trr.ProjectIDs...) {
+		projectID = strings.TrimSpace(projectID)
+		if projectID != "" && !seen[projectID] {
+			seen[projectID] = true
+			ids = append(ids, projectID)
+		}
+	}
+	return ids
+}
+
+// TimeReportForProject totals the time logged on the tasks, and their
+// subtasks, of ProjectID and ProjectIDs, optionally only for a single
+// user. Time logged on a task that is in several of the projects is
+// counted once in the totals but towards each of those projects in
+// ProjectMinutes.
+func (c *Client) TimeReportForProject(ctx context.Context, trr *TimeReportRequest) (*TimeReport, error) {
+	if err := trr.Validate(); err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Validate ensured that it is numeric if set.
+	var userID int64
+	if uid := strings.TrimSpace(string(trr.UserID)); uid != "" {
+		userID, _ = strconv.ParseInt(uid, 10, 64)
+	}
+
+	var entries []*TimeTrackingEntry
+	taskEntries := make(map[int64][]*TimeTrackingEntry)
+	projectMinutes := make(map[string]int)
+	for _, projectID := range trr.projectIDs() {
+		err := c.walkProjectTasks(ctx, projectID, func(task *Task) error {
+			kept, seen := taskEntries[task.ID]
+			if !seen {
+				all, err := c.allTimeTrackingEntriesForTask(fmt.Sprintf("%d", task.ID))
+				if err != nil {
+					return err
+				}
+				for _, entry := range all {
+					if entry.Task == nil {
+						entry.Task = &NamedAndIDdEntity{ID: task.ID, Name: task.Name}
+					}
+					if userID != 0 && (entry.CreatedBy == nil || entry.CreatedBy.ID != userID) {
+						continue
+					}
+					kept = append(kept, entry)
+				}
+				taskEntries[task.ID] = kept
+				entries = append(entries, kept...)
+			}
+			for _, entry := range kept {
+				if loggedIn(trr.Range, entry) {
+					projectMinutes[projectID] += entry.DurationMinutes
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := AggregateTimeTrackingEntries(entries, trr.Range)
+	report.ProjectMinutes = projectMinutes
+	return report, nil
+}
+
+func (c *Client) allTimeTrackingEntriesForTask(taskID string) ([]*TimeTrackingEntry, error) {
+	pagesChan, cancel, err := c.ListTimeTrackingEntriesForTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*TimeTrackingEntry
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			cancel <- true
+			return nil, err
+		}
+		entries = append(entries, page.TimeTrackingEntries...)
+	}
+	return entries, nil
+}
+
+// EstimatedTimeFieldName is the name of the custom
+// field that Asana records estimated time in.
+const EstimatedTimeFieldName = "Estimated time"
+
+// EstimatedTimeMinutes returns the estimated time of the task,
+// which is kept in a duration custom field.
+func (t *Task) EstimatedTimeMinutes() (float64, bool) {
+	cf := t.CustomFieldByName(EstimatedTimeFieldName)
+	if cf == nil || cf.Format != FormatDuration {
+		return 0, false
+	}
+	return cf.Number()
+}
@@ -0,0 +1,382 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestAggregateTimeTrackingEntries(t *testing.T) {
+	ada := &asana.NamedAndIDdEntity{ID: 1, Name: "Ada"}
+	bob := &asana.NamedAndIDdEntity{ID: 2, Name: "Bob"}
+	task1 := &asana.NamedAndIDdEntity{ID: 10}
+	task2 := &asana.NamedAndIDdEntity{ID: 20}
+	on := func(day int) *asana.Date {
+		d := asana.NewDate(2017, time.October, day)
+		return &d
+	}
+
+	entries := []*asana.TimeTrackingEntry{
+		{DurationMinutes: 30, EnteredOn: on(2), CreatedBy: ada, Task: task1},
+		{DurationMinutes: 90, EnteredOn: on(3), CreatedBy: bob, Task: task1},
+		{DurationMinutes: 45, EnteredOn: on(5), CreatedBy: ada, Task: task2},
+		{DurationMinutes: 60, EnteredOn: on(9), CreatedBy: ada, Task: task2},
+		nil,
+	}
+
+	tests := [...]struct {
+		dr        asana.DateRange
+		wantTotal int
+		wantUsers []asana.UserTime
+		wantTasks map[int64]int
+	}{
+		// The zero range covers all time.
+		0: {
+			wantTotal: 225,
+			wantUsers: []asana.UserTime{
+				{User: ada, Minutes: 135, Entries: 3},
+				{User: bob, Minutes: 90, Entries: 1},
+			},
+			wantTasks: map[int64]int{10: 120, 20: 105},
+		},
+		1: {
+			dr:        asana.DateRange{Start: *on(3), Due: *on(5)},
+			wantTotal: 135,
+			wantUsers: []asana.UserTime{
+				{User: bob, Minutes: 90, Entries: 1},
+				{User: ada, Minutes: 45, Entries: 1},
+			},
+			wantTasks: map[int64]int{10: 90, 20: 45},
+		},
+		// Open ended.
+		2: {
+			dr:        asana.DateRange{Start: *on(5)},
+			wantTotal: 105,
+			wantUsers: []asana.UserTime{{User: ada, Minutes: 105, Entries: 2}},
+			wantTasks: map[int64]int{20: 105},
+		},
+		3: {
+			dr:        asana.DateRange{Start: *on(20), Due: *on(30)},
+			wantTasks: map[int64]int{},
+		},
+	}
+
+	for i, tt := range tests {
+		report := asana.AggregateTimeTrackingEntries(entries, tt.dr)
+		if g, w := report.TotalMinutes, tt.wantTotal; g != w {
+			t.Errorf("#%d: got total %d want %d", i, g, w)
+		}
+		if g, w := len(report.Users), len(tt.wantUsers); g != w {
+			t.Errorf("#%d: got %d users want %d", i, g, w)
+			continue
+		}
+		for j, ut := range report.Users {
+			if g, w := *ut, tt.wantUsers[j]; g != w {
+				t.Errorf("#%d: user #%d: got %+v want %+v", i, j, g, w)
+			}
+		}
+		if g, w := len(report.TaskMinutes), len(tt.wantTasks); g != w {
+			t.Errorf("#%d: got %d tasks want %d", i, g, w)
+		}
+		for taskID, want := range tt.wantTasks {
+			if got := report.TaskMinutes[taskID]; got != want {
+				t.Errorf("#%d: task %d: got %d minutes want %d", i, taskID, got, want)
+			}
+		}
+	}
+}
+
+const timeTrackingEntryJSON = `{"data": {"id": 70, "duration_minutes": 45, "entered_on": "2017-10-05",
+  "created_by": {"id": 1, "name": "Ada"}, "task": {"id": 10}}}`
+
+func TestTimeTrackingEntryCRUD(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	enteredOn := asana.NewDate(2017, time.October, 5)
+
+	tests := [...]struct {
+		do          func() error
+		wantErr     bool
+		wantRequest string
+		wantBody    string
+	}{
+		0: {
+			do: func() error {
+				_, err := client.CreateTimeTrackingEntry(&asana.TimeTrackingEntryRequest{TaskID: "10"})
+				return err
+			},
+			wantErr: true,
+		},
+		1: {
+			do: func() error {
+				_, err := client.CreateTimeTrackingEntry(&asana.TimeTrackingEntryRequest{DurationMinutes: 45})
+				return err
+			},
+			wantErr: true,
+		},
+		2: {
+			do: func() error {
+				_, err := client.CreateTimeTrackingEntry(&asana.TimeTrackingEntryRequest{
+					TaskID: " 10 ", DurationMinutes: 45, EnteredOn: &enteredOn,
+				})
+				return err
+			},
+			wantRequest: "POST /api/1.0/tasks/10/time_tracking_entries",
+			wantBody:    `{"data":{"duration_minutes":45,"entered_on":"2017-10-05"}}`,
+		},
+		3: {
+			do: func() error {
+				_, err := client.FindTimeTrackingEntryByID("70")
+				return err
+			},
+			wantRequest: "GET /api/1.0/time_tracking_entries/70",
+		},
+		4: {
+			do: func() error {
+				_, err := client.UpdateTimeTrackingEntry(&asana.TimeTrackingEntryRequest{EntryID: "70", DurationMinutes: -5})
+				return err
+			},
+			wantErr: true,
+		},
+		5: {
+			// Only the date changes, the duration is left as is.
+			do: func() error {
+				_, err := client.UpdateTimeTrackingEntry(&asana.TimeTrackingEntryRequest{EntryID: "70", EnteredOn: &enteredOn})
+				return err
+			},
+			wantRequest: "PUT /api/1.0/time_tracking_entries/70",
+			wantBody:    `{"data":{"entered_on":"2017-10-05"}}`,
+		},
+		6: {
+			do:      func() error { return client.DeleteTimeTrackingEntry(" ") },
+			wantErr: true,
+		},
+		7: {
+			do:          func() error { return client.DeleteTimeTrackingEntry("70") },
+			wantRequest: "DELETE /api/1.0/time_tracking_entries/70",
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: timeTrackingEntryJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		err := tt.do()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			if len(be.reqs) != 0 {
+				t.Errorf("#%d: invalid request was sent", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.Method+" "+req.URL.Path, tt.wantRequest; g != w {
+			t.Errorf("#%d: got %q want %q", i, g, w)
+		}
+		if tt.wantBody == "" {
+			continue
+		}
+		blob, _ := ioutil.ReadAll(req.Body)
+		if g, w := string(blob), tt.wantBody; g != w {
+			t.Errorf("#%d: body:\ngot:  %s\nwant: %s", i, g, w)
+		}
+	}
+}
+
+// timeReportBackend serves project 1 with tasks 10 and 11, and
+// project 2 with tasks 11 and 12. Task 10 has subtask 13. The entries
+// of task 11 span two pages. Ada (1) and Bob (2) logged time in
+// October 2017.
+type timeReportBackend struct {
+	sync.Mutex
+	entryRequests map[string]int
+}
+
+var _ http.RoundTripper = (*timeReportBackend)(nil)
+
+func (tb *timeReportBackend) RoundTrip(req *http.Request) (*http.Response, error) {
+	tb.Lock()
+	defer tb.Unlock()
+
+	entry := func(id, userID, minutes, day int) string {
+		return fmt.Sprintf(`{"id": %d, "duration_minutes": %d, "entered_on": "2017-10-%02d", "created_by": {"id": %d}}`,
+			id, minutes, day, userID)
+	}
+
+	var blob string
+	switch path := strings.TrimPrefix(req.URL.Path, "/api/1.0"); path {
+	case "/projects/1/tasks":
+		blob = `{"data": [{"id": 10, "name": "Design"}, {"id": 11, "name": "Build"}]}`
+	case "/projects/2/tasks":
+		blob = `{"data": [{"id": 11, "name": "Build"}, {"id": 12, "name": "Support"}]}`
+	case "/tasks/10/time_tracking_entries":
+		blob = fmt.Sprintf(`{"data": [%s, %s]}`, entry(1, 1, 30, 2), entry(2, 2, 60, 3))
+	case "/tasks/11/time_tracking_entries":
+		if req.URL.Query().Get("offset") == "" {
+			blob = fmt.Sprintf(`{"data": [%s], "next_page": {"offset": "b", "path": "/tasks/11/time_tracking_entries?offset=b"}}`,
+				entry(3, 1, 45, 5))
+		} else {
+			blob = fmt.Sprintf(`{"data": [%s]}`, entry(4, 2, 15, 20))
+		}
+	case "/tasks/12/time_tracking_entries":
+		blob = fmt.Sprintf(`{"data": [%s]}`, entry(5, 1, 120, 6))
+	case "/tasks/13/time_tracking_entries":
+		blob = fmt.Sprintf(`{"data": [%s]}`, entry(6, 2, 20, 8))
+	case "/tasks/10/subtasks":
+		blob = `{"data": [{"id": 13, "name": "Review"}]}`
+	case "/tasks/11/subtasks", "/tasks/12/subtasks", "/tasks/13/subtasks":
+		blob = `{"data": []}`
+	default:
+		return makeResp("unknown path "+path, http.StatusNotFound, nil), nil
+	}
+	if strings.HasSuffix(req.URL.Path, "/time_tracking_entries") {
+		if tb.entryRequests == nil {
+			tb.entryRequests = make(map[string]int)
+		}
+		tb.entryRequests[req.URL.String()]++
+	}
+	return makeResp("200 OK", http.StatusOK, ioutil.NopCloser(strings.NewReader(blob))), nil
+}
+
+func TestListTimeTrackingEntriesForTask(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(new(timeReportBackend))
+
+	if _, _, err := client.ListTimeTrackingEntriesForTask(" "); err == nil {
+		t.Errorf("expected an error for an empty taskID")
+	}
+
+	pagesChan, _, err := client.ListTimeTrackingEntriesForTask("11")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []int64
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("unexpected page error: %v", page.Err)
+		}
+		for _, entry := range page.TimeTrackingEntries {
+			ids = append(ids, entry.ID)
+		}
+	}
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 4 {
+		t.Errorf("got entries %v want [3 4]", ids)
+	}
+}
+
+func TestTimeReportForProject(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	october := asana.DateRange{
+		Start: asana.NewDate(2017, time.October, 1),
+		Due:   asana.NewDate(2017, time.October, 10),
+	}
+
+	tests := [...]struct {
+		req             *asana.TimeReportRequest
+		wantErr         bool
+		wantTotal       int
+		wantUserMinutes map[int64]int
+		wantProjects    map[string]int
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.TimeReportRequest{ProjectIDs: []string{" "}}, wantErr: true},
+		2: {req: &asana.TimeReportRequest{ProjectID: "1", UserID: "ada@example.com"}, wantErr: true},
+		3: {
+			// The time logged on subtask 13 counts towards project 1.
+			req:             &asana.TimeReportRequest{ProjectID: "1"},
+			wantTotal:       170,
+			wantUserMinutes: map[int64]int{1: 75, 2: 95},
+			wantProjects:    map[string]int{"1": 170},
+		},
+		4: {
+			// Task 11 is in both projects but only counted once in the total.
+			req:             &asana.TimeReportRequest{ProjectID: "1", ProjectIDs: []string{"2", "1"}},
+			wantTotal:       290,
+			wantUserMinutes: map[int64]int{1: 195, 2: 95},
+			wantProjects:    map[string]int{"1": 170, "2": 180},
+		},
+		5: {
+			// A single user's time across projects.
+			req:             &asana.TimeReportRequest{ProjectIDs: []string{"1", "2"}, UserID: " 1 ", Range: october},
+			wantTotal:       195,
+			wantUserMinutes: map[int64]int{1: 195},
+			wantProjects:    map[string]int{"1": 75, "2": 165},
+		},
+		6: {
+			req:             &asana.TimeReportRequest{ProjectID: "1", UserID: "2", Range: october},
+			wantTotal:       80,
+			wantUserMinutes: map[int64]int{2: 80},
+			wantProjects:    map[string]int{"1": 80},
+		},
+	}
+
+	for i, tt := range tests {
+		be := new(timeReportBackend)
+		client.SetHTTPRoundTripper(be)
+
+		report, err := client.TimeReportForProject(context.Background(), tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		if g, w := report.TotalMinutes, tt.wantTotal; g != w {
+			t.Errorf("#%d: got total %d want %d", i, g, w)
+		}
+		userMinutes := make(map[int64]int)
+		for _, ut := range report.Users {
+			userMinutes[ut.User.ID] = ut.Minutes
+		}
+		if g, w := fmt.Sprint(userMinutes), fmt.Sprint(tt.wantUserMinutes); g != w {
+			t.Errorf("#%d: got user minutes %s want %s", i, g, w)
+		}
+		if g, w := fmt.Sprint(report.ProjectMinutes), fmt.Sprint(tt.wantProjects); g != w {
+			t.Errorf("#%d: got project minutes %s want %s", i, g, w)
+		}
+		for u, n := range be.entryRequests {
+			if n != 1 {
+				t.Errorf("#%d: %s was requested %d times", i, u, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type TypeaheadResourceType string
+
+const (
+	TypeaheadTask        TypeaheadResourceType = "task"
+	TypeaheadProject     TypeaheadResourceType = "project"
+	TypeaheadUser        TypeaheadResourceType = "user"
+	TypeaheadTag         TypeaheadResourceType = "tag"
+	TypeaheadPortfolio   TypeaheadResourceType = "portfolio"
+	TypeaheadCustomField TypeaheadResourceType = "custom_field"
+)
+
+var typeaheadResourceTypes = map[TypeaheadResourceType]bool{
+	TypeaheadTask:        true,
+	TypeaheadProject:     true,
+	TypeaheadUser:        true,
+	TypeaheadTag:         true,
+	TypeaheadPortfolio:   true,
+	TypeaheadCustomField: true,
+}
+
+// TypeaheadRequest looks up objects in a workspace whose names
+// match Query, the way that Asana's own auto-completion does.
+type TypeaheadRequest struct {
+	WorkspaceID  string                `json:"-"`
+	ResourceType TypeaheadResourceType `json:"resource_type"`
+
+	// Query is matched against the start of the words in
+	// the names. An empty query returns recently used objects.
+	Query string `json:"query,omitempty"`
+
+	// Count is the maximum number of results, at most 100.
+	Count int `json:"count,omitempty"`
+}
+
+// TypeaheadResult is a match, which can be used wherever a
+// NamedAndIDdEntity is expected e.g. in TaskRequest.Projects.
+type TypeaheadResult struct {
+	NamedAndIDdEntity
+
+	ResourceType TypeaheadResourceType `json:"resource_type,omitempty"`
+}
+
+func (tr *TypeaheadResult) Entity() *NamedAndIDdEntity {
+	if tr == nil {
+		return nil
+	}
+	entity := tr.NamedAndIDdEntity
+	return &entity
+}
+
+// UserID is for user results, to be used in e.g. TaskRequest.Followers.
+func (tr *TypeaheadResult) UserID() UserID {
+	if tr == nil {
+		return ""
+	}
+	return UserID(strconv.FormatInt(tr.ID, 10))
+}
+
+// TypeaheadEntities returns the entities of the results, in order.
+func TypeaheadEntities(results []*TypeaheadResult) []*NamedAndIDdEntity {
+	entities := make([]*NamedAndIDdEntity, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			entities = append(entities, result.Entity())
+		}
+	}
+	return entities
+}
+
+const maxTypeaheadCount = 100
+
+var (
+	errNilTypeaheadRequest  = errors.New("expecting a non-nil typeaheadRequest")
+	errEmptyResourceType    = errors.New("expecting a non-empty resourceType")
+	errTypeaheadCountTooBig = fmt.Errorf("count cannot exceed %d", maxTypeaheadCount)
+)
+
+func (tr *TypeaheadRequest) Validate() error {
+	if tr == nil {
+		return errNilTypeaheadRequest
+	}
+	if strings.TrimSpace(tr.WorkspaceID) == "" {
+		return errEmptyWorkspace
+	}
+	if tr.ResourceType == "" {
+		return errEmptyResourceType
+	}
+	if !typeaheadResourceTypes[tr.ResourceType] {
+		return fmt.Errorf("unknown resourceType %q", tr.ResourceType)
+	}
+	if tr.Count > maxTypeaheadCount {
+		return errTypeaheadCountTooBig
+	}
+	return nil
+}
+
+type typeaheadResultsWrap struct {
+	Results []*TypeaheadResult `json:"data"`
+}
+
+// Typeahead returns the objects matching the request, best match first.
+func (c *Client) Typeahead(tr *TypeaheadRequest) ([]*TypeaheadResult, error) {
+	if err := tr.Validate(); err != nil {
+		return nil, err
+	}
+
+	qs := make(url.Values)
+	qs.Set("resource_type", string(tr.ResourceType))
+	qs.Set("query", tr.Query)
+	if tr.Count > 0 {
+		qs.Set("count", strconv.Itoa(tr.Count))
+	}
+
+	fullURL := fmt.Sprintf("%s/workspaces/%s/typeahead?%s", baseURL, strings.TrimSpace(tr.WorkspaceID), qs.Encode())
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	trw := new(typeaheadResultsWrap)
+	if err := json.Unmarshal(slurp, trw); err != nil {
+		return nil, err
+	}
+	for _, result := range trw.Results {
+		if result != nil && result.ResourceType == "" {
+			result.ResourceType = tr.ResourceType
+		}
+	}
+	return trw.Results, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestTypeahead(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	tests := [...]struct {
+		req       *asana.TypeaheadRequest
+		wantErr   bool
+		wantQuery url.Values
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.TypeaheadRequest{ResourceType: asana.TypeaheadProject}, wantErr: true},
+		2: {req: &asana.TypeaheadRequest{WorkspaceID: "14916"}, wantErr: true},
+		3: {req: &asana.TypeaheadRequest{WorkspaceID: "14916", ResourceType: "projects"}, wantErr: true},
+		4: {req: &asana.TypeaheadRequest{WorkspaceID: "14916", ResourceType: asana.TypeaheadUser, Count: 101}, wantErr: true},
+		5: {
+			// An empty query is still sent, for recently used objects.
+			req:       &asana.TypeaheadRequest{WorkspaceID: " 14916 ", ResourceType: asana.TypeaheadProject},
+			wantQuery: url.Values{"resource_type": {"project"}, "query": {""}},
+		},
+		6: {
+			req: &asana.TypeaheadRequest{
+				WorkspaceID: "14916", ResourceType: asana.TypeaheadProject,
+				Query: "onboa", Count: 2,
+			},
+			wantQuery: url.Values{"resource_type": {"project"}, "query": {"onboa"}, "count": {"2"}},
+		},
+	}
+
+	resultsJSON := `{"data": [
+	  {"id": 101, "name": "Onboarding"},
+	  {"id": 102, "name": "Onboarding v2", "resource_type": "portfolio"}
+	]}`
+	for i, tt := range tests {
+		be := &scriptedBackend{resps: []scriptedResp{{code: http.StatusOK, body: resultsJSON}}}
+		client.SetHTTPRoundTripper(be)
+
+		results, err := client.Typeahead(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			if len(be.reqs) != 0 {
+				t.Errorf("#%d: invalid request was sent", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.URL.Path, "/api/1.0/workspaces/14916/typeahead"; g != w {
+			t.Errorf("#%d: got path %q want %q", i, g, w)
+		}
+		if g, w := req.URL.Query().Encode(), tt.wantQuery.Encode(); g != w {
+			t.Errorf("#%d: query:\ngot:  %s\nwant: %s", i, g, w)
+		}
+
+		// Results lacking a resource type get the requested one.
+		if len(results) != 2 {
+			t.Errorf("#%d: got %d results want 2", i, len(results))
+			continue
+		}
+		if g, w := results[0].ResourceType, asana.TypeaheadProject; g != w {
+			t.Errorf("#%d: result #0: got resource type %q want %q", i, g, w)
+		}
+		if g, w := results[1].ResourceType, asana.TypeaheadPortfolio; g != w {
+			t.Errorf("#%d: result #1: got resource type %q want %q", i, g, w)
+		}
+	}
+}
+
+func TestTypeaheadEntities(t *testing.T) {
+	results := []*asana.TypeaheadResult{
+		{NamedAndIDdEntity: asana.NamedAndIDdEntity{ID: 7, Name: "Ada"}, ResourceType: asana.TypeaheadUser},
+		nil,
+		{NamedAndIDdEntity: asana.NamedAndIDdEntity{ID: 9, Name: "Bob"}, ResourceType: asana.TypeaheadUser},
+	}
+
+	entities := asana.TypeaheadEntities(results)
+	if len(entities) != 2 {
+		t.Fatalf("got %d entities want 2", len(entities))
+	}
+	if entities[0].ID != 7 || entities[0].Name != "Ada" || entities[1].ID != 9 {
+		t.Errorf("unexpected entities: %+v, %+v", entities[0], entities[1])
+	}
+
+	// The entities are copies that don't alias the results.
+	entities[0].Name = "Changed"
+	if results[0].Name != "Ada" {
+		t.Errorf("changing an entity changed its result")
+	}
+
+	if g, w := results[2].UserID(), asana.UserID("9"); g != w {
+		t.Errorf("got userID %q want %q", g, w)
+	}
+	var missing *asana.TypeaheadResult
+	if g := missing.UserID(); g != "" {
+		t.Errorf("nil result: got userID %q want \"\"", g)
+	}
+	if g := missing.Entity(); g != nil {
+		t.Errorf("nil result: got entity %+v want nil", g)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UserTaskList is a user's "My Tasks" list in a workspace.
+type UserTaskList struct {
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+
+	Owner     *NamedAndIDdEntity `json:"owner,omitempty"`
+	Workspace *NamedAndIDdEntity `json:"workspace,omitempty"`
+}
+
+var errEmptyUserTaskListID = errors.New("expecting a non-empty userTaskListID")
+
+type userTaskListWrap struct {
+	UserTaskList *UserTaskList `json:"data"`
+}
+
+func parseOutUserTaskListFromData(blob []byte) (*UserTaskList, error) {
+	utlw := new(userTaskListWrap)
+	if err := json.Unmarshal(blob, utlw); err != nil {
+		return nil, err
+	}
+	return utlw.UserTaskList, nil
+}
+
+// FindUserTaskList retrieves the task list of a user in a workspace.
+// An empty userID is taken to mean the authenticated user.
+func (c *Client) FindUserTaskList(userID UserID, workspaceID string) (*UserTaskList, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, errEmptyWorkspace
+	}
+	qs := make(url.Values)
+	qs.Set("workspace", workspaceID)
+	fullURL := fmt.Sprintf("%s/users/%s/user_task_list?%s", baseURL, userID, qs.Encode())
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutUserTaskListFromData(slurp)
+}
+
+func (c *Client) FindUserTaskListByID(userTaskListID string) (*UserTaskList, error) {
+	userTaskListID = strings.TrimSpace(userTaskListID)
+	if userTaskListID == "" {
+		return nil, errEmptyUserTaskListID
+	}
+	fullURL := fmt.Sprintf("%s/user_task_lists/%s", baseURL, userTaskListID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutUserTaskListFromData(slurp)
+}
+
+type UserTaskListRequest struct {
+	UserTaskListID string `json:"-"`
+
+	// CompletedSince if set returns only tasks that are incomplete
+	// or that were completed after it. Without it, completed tasks
+	// are omitted from the listing.
+	CompletedSince *time.Time `json:"completed_since,omitempty"`
+
+	Limit int `json:"limit,omitempty"`
+}
+
+var errNilUserTaskListRequest = errors.New("expecting a non-nil userTaskListRequest")
+
+func (utlr *UserTaskListRequest) Validate() error {
+	if utlr == nil {
+		return errNilUserTaskListRequest
+	}
+	if strings.TrimSpace(utlr.UserTaskListID) == "" {
+		return errEmptyUserTaskListID
+	}
+	return nil
+}
+
+// ListTasksInUserTaskList lists the tasks in a user's task list.
+// Only the tasks' names, completion, dates and assignee
+// sections and statuses are retrieved.
+func (c *Client) ListTasksInUserTaskList(utlr *UserTaskListRequest) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
+	if err := utlr.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	qs := make(url.Values)
+	if utlr.CompletedSince != nil {
+		qs.Set("completed_since", utlr.CompletedSince.UTC().Format(time.RFC3339))
+	} else {
+		// Asana's sentinel value for incomplete tasks only.
+		qs.Set("completed_since", "now")
+	}
+	limit := utlr.Limit
+	if limit <= 0 {
+		limit = defaultTaskLimit
+	}
+	qs.Set("limit", strconv.Itoa(limit))
+	// The section of each task is only returned when explicitly asked for.
+	qs.Set("opt_fields", "name,completed,completed_at,due_on,start_on,assignee_status,assignee_section.name")
+
+	path := fmt.Sprintf("/user_task_lists/%s/tasks?%s", strings.TrimSpace(utlr.UserTaskListID), qs.Encode())
+	return c.doTasksPaging(path)
+}
+
+// Section is a grouping of tasks in a project or in a user's task list.
+type Section struct {
+	ID        int64      `json:"id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	Project *NamedAndIDdEntity `json:"project,omitempty"`
+}
+
+type SectionsPage struct {
+	Sections []*Section `json:"data"`
+	Err      error
+}
+
+type sectionsPager struct {
+	SectionsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListUserTaskListSections lists the sections of a
+// user's task list in the order that they appear.
+func (c *Client) ListUserTaskListSections(userTaskListID string) (pagesChan chan *SectionsPage, cancelChan chan<- bool, err error) {
+	userTaskListID = strings.TrimSpace(userTaskListID)
+	if userTaskListID == "" {
+		return nil, nil, errEmptyUserTaskListID
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *SectionsPage)
+
+	// The sections of a user task list are
+	// served from the projects endpoint.
+	go c.paginate(fmt.Sprintf("/projects/%s/sections", userTaskListID), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(sectionsPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.SectionsPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
+
+// assigneeStatusSections are the names of the default
+// sections that replaced each of the assignee statuses.
+var assigneeStatusSections = map[AssigneeStatus]string{
+	StatusInbox:    "Recently assigned",
+	StatusToday:    "Today",
+	StatusUpcoming: "Upcoming",
+	StatusLater:    "Later",
+}
+
+// SectionName returns the name of the default My Tasks section
+// that replaced the assignee status, or "" if there isn't one.
+func (as AssigneeStatus) SectionName() string {
+	return assigneeStatusSections[as]
+}
+
+// SectionForAssigneeStatus finds the section amongst those of a user
+// task list that corresponds to the legacy assignee status. Sections
+// are matched by name, so it returns nil if the user renamed or deleted
+// the default section.
+func SectionForAssigneeStatus(sections []*Section, as AssigneeStatus) *Section {
+	name := as.SectionName()
+	if name == "" {
+		return nil
+	}
+	for _, section := range sections {
+		if section != nil && strings.EqualFold(strings.TrimSpace(section.Name), name) {
+			return section
+		}
+	}
+	return nil
+}
+
+// AssigneeStatusForSection is the inverse of SectionForAssigneeStatus.
+// ok is false if the section isn't one of the defaults.
+func AssigneeStatusForSection(section *Section) (as AssigneeStatus, ok bool) {
+	if section == nil {
+		return "", false
+	}
+	name := strings.TrimSpace(section.Name)
+	for status, sectionName := range assigneeStatusSections {
+		if strings.EqualFold(name, sectionName) {
+			return status, true
+		}
+	}
+	return "", false
+}
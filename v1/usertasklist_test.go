@@ -0,0 +1,176 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestSectionForAssigneeStatus(t *testing.T) {
+	sections := []*asana.Section{
+		{ID: 1, Name: "Recently assigned"},
+		{ID: 2, Name: "today"},
+		{ID: 3, Name: "Waiting on others"},
+		{ID: 4, Name: "Later"},
+	}
+
+	tests := [...]struct {
+		status asana.AssigneeStatus
+		wantID int64
+	}{
+		0: {status: asana.StatusInbox, wantID: 1},
+		// Matching is case insensitive.
+		1: {status: asana.StatusToday, wantID: 2},
+		// The "Upcoming" section was removed.
+		2: {status: asana.StatusUpcoming},
+		3: {status: asana.StatusLater, wantID: 4},
+		4: {status: "unknown"},
+	}
+
+	for i, tt := range tests {
+		section := asana.SectionForAssigneeStatus(sections, tt.status)
+		var gotID int64
+		if section != nil {
+			gotID = section.ID
+		}
+		if gotID != tt.wantID {
+			t.Errorf("#%d: got section %d want %d", i, gotID, tt.wantID)
+			continue
+		}
+		if section == nil {
+			continue
+		}
+		status, ok := asana.AssigneeStatusForSection(section)
+		if !ok || status != tt.status {
+			t.Errorf("#%d: got reverse mapping (%q, %v) want %q", i, status, ok, tt.status)
+		}
+	}
+
+	if status, ok := asana.AssigneeStatusForSection(sections[2]); ok {
+		t.Errorf("custom section unexpectedly mapped to %q", status)
+	}
+}
+
+func TestListTasksInUserTaskList(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+
+	since := time.Date(2017, time.October, 1, 0, 0, 0, 0, time.UTC)
+	tests := [...]struct {
+		req           *asana.UserTaskListRequest
+		wantErr       bool
+		wantCompleted string
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.UserTaskListRequest{}, wantErr: true},
+		2: {req: &asana.UserTaskListRequest{UserTaskListID: "91"}, wantCompleted: "now"},
+		3: {
+			req:           &asana.UserTaskListRequest{UserTaskListID: "91", CompletedSince: &since},
+			wantCompleted: "2017-10-01T00:00:00Z",
+		},
+	}
+
+	for i, tt := range tests {
+		be := &scriptedBackend{
+			resps: []scriptedResp{{code: http.StatusOK, body: `{"data": [
+			  {"id": 1, "name": "Review", "assignee_status": "today",
+			   "assignee_section": {"id": 2, "name": "Today"}}
+			]}`}},
+		}
+		client.SetHTTPRoundTripper(be)
+
+		pagesChan, _, err := client.ListTasksInUserTaskList(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+
+		var tasks []*asana.Task
+		for page := range pagesChan {
+			if err := page.Err; err != nil {
+				t.Errorf("#%d: page error: %v", i, err)
+			}
+			tasks = append(tasks, page.Tasks...)
+		}
+		if len(tasks) != 1 || tasks[0].AssigneeSection == nil || tasks[0].AssigneeSection.Name != "Today" {
+			t.Errorf("#%d: unexpected tasks: %+v", i, tasks)
+		}
+
+		if len(be.reqs) != 1 {
+			t.Errorf("#%d: got %d requests want 1", i, len(be.reqs))
+			continue
+		}
+		req := be.reqs[0]
+		if g, w := req.URL.Path, "/api/1.0/user_task_lists/91/tasks"; g != w {
+			t.Errorf("#%d: got path %q want %q", i, g, w)
+		}
+		if g, w := req.URL.Query().Get("completed_since"), tt.wantCompleted; g != w {
+			t.Errorf("#%d: got completed_since %q want %q", i, g, w)
+		}
+	}
+}
+
+func TestListUserTaskListSections(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	if _, _, err := client.ListUserTaskListSections(" "); err == nil {
+		t.Errorf("expected an error for an empty userTaskListID")
+	}
+
+	be := &scriptedBackend{
+		resps: []scriptedResp{
+			{code: http.StatusOK, body: `{"data": [{"id": 1, "name": "Recently assigned"}],
+			  "next_page": {"offset": "a", "path": "/projects/91/sections?offset=a"}}`},
+			{code: http.StatusOK, body: `{"data": [{"id": 2, "name": "Today"}]}`},
+		},
+	}
+	client.SetHTTPRoundTripper(be)
+
+	pagesChan, _, err := client.ListUserTaskListSections("91")
+	if err != nil {
+		t.Fatalf("listing: %v", err)
+	}
+	var sections []*asana.Section
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			t.Errorf("page error: %v", err)
+		}
+		sections = append(sections, page.Sections...)
+	}
+	// Sections past the first page must not be dropped.
+	if section := asana.SectionForAssigneeStatus(sections, asana.StatusToday); section == nil || section.ID != 2 {
+		t.Errorf("got %+v want the Today section", section)
+	}
+	if g, w := be.reqs[0].URL.Path, "/api/1.0/projects/91/sections"; g != w {
+		t.Errorf("got path %q want %q", g, w)
+	}
+	if g, w := be.reqs[1].URL.Query().Get("offset"), "a"; g != w {
+		t.Errorf("got offset %q want %q", g, w)
+	}
+}
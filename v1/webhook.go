@@ -0,0 +1,392 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookFilter narrows down the events delivered to a webhook.
+// Unset fields match anything.
+type WebhookFilter struct {
+	ResourceType    string      `json:"resource_type,omitempty"`
+	ResourceSubtype string      `json:"resource_subtype,omitempty"`
+	Action          EventAction `json:"action,omitempty"`
+
+	// Fields restricts "changed" events to changes of these fields.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Matches reports whether the event passes the filter.
+// A nil filter matches every event.
+func (wf *WebhookFilter) Matches(ev *Event) bool {
+	if wf == nil {
+		return true
+	}
+	if ev == nil {
+		return false
+	}
+	if wf.Action != "" && wf.Action != ev.Action {
+		return false
+	}
+	if res := ev.Resource; res != nil {
+		if wf.ResourceType != "" && wf.ResourceType != res.ResourceType {
+			return false
+		}
+		if wf.ResourceSubtype != "" && wf.ResourceSubtype != res.ResourceSubtype {
+			return false
+		}
+	} else if wf.ResourceType != "" || wf.ResourceSubtype != "" {
+		return false
+	}
+	if len(wf.Fields) == 0 {
+		return true
+	}
+	if ev.Change == nil {
+		return false
+	}
+	for _, field := range wf.Fields {
+		if field == ev.Change.Field {
+			return true
+		}
+	}
+	return false
+}
+
+type Webhook struct {
+	ID       int64          `json:"id,omitempty"`
+	Resource *EventResource `json:"resource,omitempty"`
+	Target   string         `json:"target,omitempty"`
+	Active   bool           `json:"active"`
+
+	Filters []*WebhookFilter `json:"filters,omitempty"`
+
+	CreatedAt          *time.Time `json:"created_at,omitempty"`
+	LastSuccessAt      *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt      *time.Time `json:"last_failure_at,omitempty"`
+	LastFailureContent string     `json:"last_failure_content,omitempty"`
+}
+
+type WebhookRequest struct {
+	// ResourceID is the project, task, portfolio
+	// or other resource whose events to deliver.
+	ResourceID string `json:"resource"`
+
+	// Target is the URL that events are delivered to. It must
+	// complete the handshake while the webhook is being created,
+	// see WebhookReceiver.
+	Target string `json:"target"`
+
+	Filters []*WebhookFilter `json:"filters,omitempty"`
+}
+
+var (
+	errNilWebhookRequest = errors.New("expecting a non-nil webhookRequest")
+	errEmptyTarget       = errors.New("expecting a non-empty target")
+	errEmptyWebhookID    = errors.New("expecting a non-empty webhookID")
+)
+
+func (wr *WebhookRequest) Validate() error {
+	if wr == nil {
+		return errNilWebhookRequest
+	}
+	if strings.TrimSpace(wr.ResourceID) == "" {
+		return errEmptyResourceID
+	}
+	if strings.TrimSpace(wr.Target) == "" {
+		return errEmptyTarget
+	}
+	return nil
+}
+
+type webhookWrap struct {
+	Webhook *Webhook `json:"data"`
+}
+
+func parseOutWebhookFromData(blob []byte) (*Webhook, error) {
+	ww := new(webhookWrap)
+	if err := json.Unmarshal(blob, ww); err != nil {
+		return nil, err
+	}
+	return ww.Webhook, nil
+}
+
+// CreateWebhook registers a webhook. Asana performs the handshake with the
+// target before responding, so the receiver must already be listening.
+func (c *Client) CreateWebhook(wr *WebhookRequest) (*Webhook, error) {
+	if err := wr.Validate(); err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doJSONReqThenSlurpBody("POST", "/webhooks", wr)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutWebhookFromData(slurp)
+}
+
+func (c *Client) FindWebhookByID(webhookID string) (*Webhook, error) {
+	webhookID = strings.TrimSpace(webhookID)
+	if webhookID == "" {
+		return nil, errEmptyWebhookID
+	}
+	fullURL := fmt.Sprintf("%s/webhooks/%s", baseURL, webhookID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutWebhookFromData(slurp)
+}
+
+func (c *Client) DeleteWebhook(webhookID string) error {
+	webhookID = strings.TrimSpace(webhookID)
+	if webhookID == "" {
+		return errEmptyWebhookID
+	}
+	fullURL := fmt.Sprintf("%s/webhooks/%s", baseURL, webhookID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type WebhookQuery struct {
+	WorkspaceID string `json:"workspace"`
+
+	// ResourceID optionally restricts the
+	// results to webhooks on that resource.
+	ResourceID string `json:"resource,omitempty"`
+}
+
+type WebhooksPage struct {
+	Webhooks []*Webhook `json:"data"`
+	Err      error
+}
+
+type webhooksPager struct {
+	WebhooksPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+var errNilWebhookQuery = errors.New("expecting a non-nil webhookQuery")
+
+func (c *Client) ListWebhooks(wq *WebhookQuery) (pagesChan chan *WebhooksPage, cancelChan chan<- bool, err error) {
+	if wq == nil {
+		return nil, nil, errNilWebhookQuery
+	}
+	if strings.TrimSpace(wq.WorkspaceID) == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+
+	qs := make(url.Values)
+	qs.Set("workspace", strings.TrimSpace(wq.WorkspaceID))
+	if resourceID := strings.TrimSpace(wq.ResourceID); resourceID != "" {
+		qs.Set("resource", resourceID)
+	}
+
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *WebhooksPage)
+
+	go c.paginate(fmt.Sprintf("/webhooks?%s", qs.Encode()), pagesChan, cancel, func(slurp []byte, err error) (interface{}, *pageToken) {
+		pager := new(webhooksPager)
+		if err == nil {
+			err = json.Unmarshal(slurp, pager)
+		}
+		pager.Err = err
+		return &pager.WebhooksPage, pager.NextPage
+	})
+
+	return pagesChan, cancel, nil
+}
+
+const (
+	hookSecretHeader    = "X-Hook-Secret"
+	hookSignatureHeader = "X-Hook-Signature"
+)
+
+// WebhookCallback is invoked for each delivered event that matches the
+// filter it was registered with. Returning an error makes the receiver
+// reply with a failure so that Asana retries the delivery.
+type WebhookCallback func(*Event) error
+
+type webhookRoute struct {
+	filter   *WebhookFilter
+	callback WebhookCallback
+}
+
+// WebhookReceiver is an http.Handler that completes the webhook
+// handshake, verifies the signature of every delivery and dispatches
+// the delivered events to the callbacks registered with HandleFunc.
+//
+// Only a single handshake is ever accepted, and none if the receiver was
+// created with a secret, so that nobody can later swap the secret for one
+// that they know. A receiver for a recreated webhook must be created anew.
+type WebhookReceiver struct {
+	mu     sync.RWMutex
+	secret string
+	routes []*webhookRoute
+
+	// handshaking is set while OnHandshake vets a handshake.
+	handshaking bool
+
+	// OnHandshake, if set, is invoked with the handshake request and its
+	// secret, for example to check that the request comes from a webhook
+	// that is being created and to persist the secret. Returning an error
+	// rejects the handshake and with it the creation of the webhook.
+	OnHandshake func(req *http.Request, secret string) error
+}
+
+var _ http.Handler = (*WebhookReceiver)(nil)
+
+// NewWebhookReceiver creates a receiver. The secret is the one received
+// in a previous handshake; leave it empty for a webhook yet to be created.
+func NewWebhookReceiver(secret string) *WebhookReceiver {
+	return &WebhookReceiver{secret: secret}
+}
+
+// Secret returns the secret that deliveries are verified against.
+func (wr *WebhookReceiver) Secret() string {
+	wr.mu.RLock()
+	defer wr.mu.RUnlock()
+	return wr.secret
+}
+
+// HandleFunc registers a callback for the events that match filter.
+// A nil filter matches every event.
+func (wr *WebhookReceiver) HandleFunc(filter *WebhookFilter, callback WebhookCallback) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.routes = append(wr.routes, &webhookRoute{filter: filter, callback: callback})
+}
+
+type webhookDelivery struct {
+	Events []*Event `json:"events"`
+}
+
+// ValidWebhookSignature reports whether signature is the hex encoded
+// HMAC-SHA256 of body keyed with the webhook's secret.
+func ValidWebhookSignature(secret string, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	want := mac.Sum(nil)
+	got, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, want)
+}
+
+func (wr *WebhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		http.Error(rw, "only POST is accepted", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if secret := req.Header.Get(hookSecretHeader); secret != "" {
+		wr.handshake(rw, req, secret)
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	secret := wr.Secret()
+	if secret == "" || !ValidWebhookSignature(secret, body, req.Header.Get(hookSignatureHeader)) {
+		http.Error(rw, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	delivery := new(webhookDelivery)
+	if err := json.Unmarshal(body, delivery); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	wr.mu.RLock()
+	routes := wr.routes[:len(wr.routes):len(wr.routes)]
+	wr.mu.RUnlock()
+
+	var failed bool
+	for _, ev := range delivery.Events {
+		for _, route := range routes {
+			if !route.filter.Matches(ev) {
+				continue
+			}
+			if err := route.callback(ev); err != nil {
+				failed = true
+			}
+		}
+	}
+	if failed {
+		http.Error(rw, "failed to process some events", http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+const alreadyHandshakenMsg = "a handshake was already completed"
+
+func (wr *WebhookReceiver) handshake(rw http.ResponseWriter, req *http.Request, secret string) {
+	// Checking for and claiming the handshake happen under the same
+	// lock so that of concurrent handshakes, only one can succeed.
+	wr.mu.Lock()
+	if wr.secret != "" || wr.handshaking {
+		wr.mu.Unlock()
+		http.Error(rw, alreadyHandshakenMsg, http.StatusConflict)
+		return
+	}
+	wr.handshaking = true
+	wr.mu.Unlock()
+
+	var err error
+	if wr.OnHandshake != nil {
+		err = wr.OnHandshake(req, secret)
+	}
+
+	wr.mu.Lock()
+	wr.handshaking = false
+	if err == nil {
+		wr.secret = secret
+	}
+	wr.mu.Unlock()
+
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusForbidden)
+		return
+	}
+	rw.Header().Set(hookSecretHeader, secret)
+	rw.WriteHeader(http.StatusOK)
+}
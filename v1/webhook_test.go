@@ -0,0 +1,196 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func postToHook(t *testing.T, url string, headers map[string]string, body string) *http.Response {
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	return res
+}
+
+func TestWebhookReceiver(t *testing.T) {
+	receiver := asana.NewWebhookReceiver("")
+
+	var completed, all []int64
+	receiver.HandleFunc(&asana.WebhookFilter{
+		ResourceType: "task",
+		Action:       asana.ActionChanged,
+		Fields:       []string{"completed"},
+	}, func(ev *asana.Event) error {
+		completed = append(completed, ev.Resource.ID)
+		return nil
+	})
+	receiver.HandleFunc(nil, func(ev *asana.Event) error {
+		all = append(all, ev.Resource.ID)
+		return nil
+	})
+
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	// 1. The handshake must echo the secret back.
+	res := postToHook(t, srv.URL, map[string]string{"X-Hook-Secret": "s3cr3t"}, "")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("handshake: got status %d", res.StatusCode)
+	}
+	if got := res.Header.Get("X-Hook-Secret"); got != "s3cr3t" {
+		t.Fatalf("handshake: got echoed secret %q", got)
+	}
+
+	// 2. A second handshake must not replace the secret.
+	res = postToHook(t, srv.URL, map[string]string{"X-Hook-Secret": "evil"}, "")
+	if res.StatusCode == http.StatusOK || receiver.Secret() != "s3cr3t" {
+		t.Fatalf("second handshake: got status %d and secret %q", res.StatusCode, receiver.Secret())
+	}
+
+	body := `{"events": [
+	  {"action": "changed", "resource": {"id": 1, "resource_type": "task"}, "change": {"field": "completed"}},
+	  {"action": "changed", "resource": {"id": 2, "resource_type": "task"}, "change": {"field": "name"}},
+	  {"action": "added", "resource": {"id": 3, "resource_type": "story"}}
+	]}`
+
+	// 3. Deliveries with a bad signature must be rejected.
+	res = postToHook(t, srv.URL, map[string]string{"X-Hook-Signature": sign("wrong", body)}, body)
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("bad signature: got status %d want %d", res.StatusCode, http.StatusUnauthorized)
+	}
+	if len(all) != 0 {
+		t.Fatalf("bad signature: callbacks were invoked")
+	}
+
+	// 4. Signed deliveries are dispatched to matching callbacks.
+	res = postToHook(t, srv.URL, map[string]string{"X-Hook-Signature": sign("s3cr3t", body)}, body)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("delivery: got status %d", res.StatusCode)
+	}
+	if len(completed) != 1 || completed[0] != 1 {
+		t.Errorf("filtered callback: got %v want [1]", completed)
+	}
+	if len(all) != 3 {
+		t.Errorf("catch-all callback: got %v want 3 events", all)
+	}
+}
+
+func TestWebhookReceiverHandshake(t *testing.T) {
+	// 1. OnHandshake vets the request and can reject a spoofed handshake.
+	receiver := asana.NewWebhookReceiver("")
+	var handshakes int32
+	receiver.OnHandshake = func(req *http.Request, secret string) error {
+		atomic.AddInt32(&handshakes, 1)
+		if req.URL.Query().Get("token") != "t0k3n" {
+			return errors.New("unexpected handshake")
+		}
+		return nil
+	}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	res := postToHook(t, srv.URL, map[string]string{"X-Hook-Secret": "evil"}, "")
+	if res.StatusCode == http.StatusOK || receiver.Secret() != "" {
+		t.Fatalf("spoofed handshake: got status %d and secret %q", res.StatusCode, receiver.Secret())
+	}
+	res = postToHook(t, srv.URL+"?token=t0k3n", map[string]string{"X-Hook-Secret": "s3cr3t"}, "")
+	if res.StatusCode != http.StatusOK || receiver.Secret() != "s3cr3t" {
+		t.Fatalf("handshake: got status %d and secret %q", res.StatusCode, receiver.Secret())
+	}
+
+	// 2. Even OnHandshake isn't consulted once the secret is set.
+	res = postToHook(t, srv.URL+"?token=t0k3n", map[string]string{"X-Hook-Secret": "other"}, "")
+	if res.StatusCode != http.StatusConflict || receiver.Secret() != "s3cr3t" {
+		t.Fatalf("later handshake: got status %d and secret %q", res.StatusCode, receiver.Secret())
+	}
+	if g, w := atomic.LoadInt32(&handshakes), int32(2); g != w {
+		t.Errorf("OnHandshake: got %d calls want %d", g, w)
+	}
+
+	// 3. Nor when the receiver was created with a secret.
+	preset := httptest.NewServer(asana.NewWebhookReceiver("known"))
+	defer preset.Close()
+	res = postToHook(t, preset.URL, map[string]string{"X-Hook-Secret": "evil"}, "")
+	if res.StatusCode != http.StatusConflict {
+		t.Errorf("preset secret: got status %d want %d", res.StatusCode, http.StatusConflict)
+	}
+}
+
+func TestWebhookReceiverConcurrentHandshakes(t *testing.T) {
+	receiver := asana.NewWebhookReceiver("")
+	release := make(chan bool)
+	receiver.OnHandshake = func(req *http.Request, secret string) error {
+		<-release
+		return nil
+	}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	const n = 8
+	codes := make(chan int, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			secret := fmt.Sprintf("secret-%d", i)
+			res := postToHook(t, srv.URL, map[string]string{"X-Hook-Secret": secret}, "")
+			codes <- res.StatusCode
+		}(i)
+	}
+
+	// All but the handshake being vetted are turned away.
+	for i := 0; i < n-1; i++ {
+		if code := <-codes; code != http.StatusConflict {
+			t.Errorf("got status %d want %d", code, http.StatusConflict)
+		}
+	}
+	close(release)
+	wg.Wait()
+	if code := <-codes; code != http.StatusOK {
+		t.Errorf("got status %d want %d", code, http.StatusOK)
+	}
+	if receiver.Secret() == "" {
+		t.Errorf("expected the secret to be set")
+	}
+}